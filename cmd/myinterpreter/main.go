@@ -1,358 +1,211 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"strconv"
 	"strings"
-)
 
-const (
-	LEFT_PAREN    = "LEFT_PAREN"
-	RIGHT_PAREN   = "RIGHT_PAREN"
-	LEFT_BRACE    = "LEFT_BRACE"
-	RIGHT_BRACE   = "RIGHT_BRACE"
-	STAR          = "STAR"
-	DOT           = "DOT"
-	COMMA         = "COMMA"
-	PLUS          = "PLUS"
-	MINUS         = "MINUS"
-	SEMICOLON     = "SEMICOLON"
-	EQUAL         = "EQUAL"
-	EQUAL_EQUAL   = "EQUAL_EQUAL"
-	BANG          = "BANG"
-	BANG_EQUAL    = "BANG_EQUAL"
-	LESS          = "LESS"
-	LESS_EQUAL    = "LESS_EQUAL"
-	GREATER       = "GREATER"
-	GREATER_EQUAL = "GREATER_EQUAL"
-	SLASH         = "SLASH"
-	STRING        = "STRING"
-	NUMBER        = "NUMBER"
-	IDENTIFIER    = "IDENTIFIER"
-	AND           = "AND"
-	CLASS         = "CLASS"
-	ELSE          = "ELSE"
-	FALSE         = "FALSE"
-	FOR           = "FOR"
-	FUN           = "FUN"
-	IF            = "IF"
-	NIL           = "NIL"
-	OR            = "OR"
-	PRINT         = "PRINT"
-	RETURN        = "RETURN"
-	SUPER         = "SUPER"
-	THIS          = "THIS"
-	TRUE          = "TRUE"
-	VAR           = "VAR"
-	WHILE         = "WHILE"
+	"github.com/codecrafters-io/interpreter-starter-go/lox"
 )
 
-var keywords = map[string]string{
-	"and":    AND,
-	"class":  CLASS,
-	"else":   ELSE,
-	"false":  FALSE,
-	"for":    FOR,
-	"fun":    FUN,
-	"if":     IF,
-	"nil":    NIL,
-	"or":     OR,
-	"print":  PRINT,
-	"return": RETURN,
-	"super":  SUPER,
-	"this":   THIS,
-	"true":   TRUE,
-	"var":    VAR,
-	"while":  WHILE,
-}
-
-type Token struct {
-	TokenType string
-	lexeme    string
-	literal   string
-}
-
-func (token *Token) setToken(args ...string) {
-	token.TokenType = args[0]
-	token.lexeme = args[1]
-	if len(args) > 2 {
-		token.literal = args[2]
-	} else {
-		token.literal = "null"
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "repl" {
+		if err := lox.New(lox.Options{}).REPL(os.Stdin); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
 	}
-}
-
-func (token *Token) printToken() {
-	fmt.Printf("%s %s %s\n", token.TokenType, token.lexeme, token.literal)
-}
-
-var exitCode = 0
-var fileContentString string
-var line = 1
 
-func main() {
 	if len(os.Args) < 3 {
-		fmt.Fprintln(os.Stderr, "Usage: ./your_program.sh tokenize <filename>")
-		os.Exit(1)
+		usage()
 	}
 
 	command := os.Args[1]
+	opts, args := parseFlags(os.Args[2:])
+	if len(args) == 0 {
+		usage()
+	}
 
-	filename := os.Args[2]
-	fileContents, err := os.ReadFile(filename)
+	source, err := readSource(args)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
 		os.Exit(1)
 	}
 
+	interpreter := lox.New(lox.Options{
+		ErrorFormat: opts.errorFormat,
+		Snippets:    true,
+		Color:       isTerminal(os.Stderr) && os.Getenv("NO_COLOR") == "",
+
+		UnicodeIdentifiers: opts.unicodeIdentifiers,
+	})
+
 	switch command {
 	case "tokenize":
-		tokens := tokenizeFile(fileContents)
+		tokens, err := interpreter.Tokenize(source)
 
-		for _, token := range tokens {
-			if token != (Token{}) {
-				token.printToken()
+		if opts.format == "json" {
+			// JSON output is for tools, so comments are included
+			tokens = withComments(tokens, interpreter.Comments(source))
+			printJSON(json.MarshalIndent(tokens, "", "  "))
+		} else {
+			for _, token := range tokens {
+				fmt.Println(token)
 			}
 		}
 
-		fmt.Println("EOF  null")
+		os.Exit(exitCode(err))
 	case "parse":
-		expr, err := parseFile(fileContents)
+		expr, err := interpreter.Parse(source)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "[line %d] %v\n", line, err)
-			os.Exit(65)
+			os.Exit(exitCode(err))
 		}
 
-		fmt.Println(expr)
+		if opts.format == "json" {
+			printJSON(lox.MarshalAST(expr))
+		} else {
+			fmt.Println(expr)
+		}
 	case "evaluate":
-		val, err := evaluate(fileContents)
+		val, err := interpreter.Eval(source)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "[line %d] %v\n", line, err)
-			if exitCode == 0 {
-				exitCode = 70
-			}
-			os.Exit(exitCode)
+			os.Exit(exitCode(err))
 		}
 
-		fmt.Println(val)
+		fmt.Println(lox.Stringify(val))
 	case "run":
-		err := run(fileContents)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "[line %d] %v\n", lineNumber, err)
-			if exitCode == 0 {
-				exitCode = 70
-			}
-			os.Exit(exitCode)
-		}
+		err := interpreter.Run(source)
+		os.Exit(exitCode(err))
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", command)
 		os.Exit(1)
 	}
-
-	os.Exit(exitCode)
 }
 
-func tokenizeFile(fileContents []byte) []Token {
-	fileContentString = string(fileContents)
-
-	tokens := []Token{}
-	for i := 0; i < len(fileContentString); i++ {
-		newToken, err := addToken(string(fileContentString[i]), &i)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "[line %d] %v\n", line, err)
-		}
-		// fmt.Printf("Token : %+v\n", newToken)
-		if newToken != (Token{}) {
-			tokens = append(tokens, newToken)
+// withComments merges comments into tokens in source order.
+func withComments(tokens []lox.Token, comments []lox.Token) []lox.Token {
+	merged := make([]lox.Token, 0, len(tokens)+len(comments))
+	for _, token := range tokens {
+		for len(comments) > 0 && comments[0].Span().Start().Offset() < token.Span().Start().Offset() {
+			merged = append(merged, comments[0])
+			comments = comments[1:]
 		}
+		merged = append(merged, token)
 	}
 
-	return tokens
+	return merged
 }
 
-func addToken(ch string, index *int) (Token, error) {
-	token := Token{}
+// options holds the flags given after the command.
+type options struct {
+	// format is the output format of tokenize and parse: "text" or "json".
+	format string
+	// errorFormat is how errors are written to stderr: "text" or "json".
+	errorFormat string
+	// unicodeIdentifiers allows non-ASCII letters in identifiers.
+	unicodeIdentifiers bool
+}
 
-	switch ch {
-	case "(":
-		token.setToken(LEFT_PAREN, ch)
-	case ")":
-		token.setToken(RIGHT_PAREN, ch)
-	case "{":
-		token.setToken(LEFT_BRACE, ch)
-	case "}":
-		token.setToken(RIGHT_BRACE, ch)
-	case ",":
-		token.setToken(COMMA, ch)
-	case ".":
-		token.setToken(DOT, ch)
-	case "*":
-		token.setToken(STAR, ch)
-	case "+":
-		token.setToken(PLUS, ch)
-	case "-":
-		token.setToken(MINUS, ch)
-	case ";":
-		token.setToken(SEMICOLON, ch)
-	case "=":
-		if nextToken(*index) == "=" {
-			token.setToken(EQUAL_EQUAL, "==")
-			*index++
-		} else {
-			token.setToken(EQUAL, ch)
-		}
-	case "!":
-		if nextToken(*index) == "=" {
-			token.setToken(BANG_EQUAL, "!=")
-			*index++
-		} else {
-			token.setToken(BANG, ch)
-		}
-	case "<":
-		if nextToken(*index) == "=" {
-			token.setToken(LESS_EQUAL, "<=")
-			*index++
-		} else {
-			token.setToken(LESS, ch)
-		}
-	case ">":
-		if nextToken(*index) == "=" {
-			token.setToken(GREATER_EQUAL, ">=")
-			*index++
-		} else {
-			token.setToken(GREATER, ch)
-		}
-	case "/":
-		if nextToken(*index) != "/" {
-			token.setToken(SLASH, ch)
-		} else {
-			for *index < len(fileContentString) && fileContentString[*index] != '\n' {
-				*index++
-			}
-			line++
-		}
-	case " ", "\t":
-		break
-	case "\n":
-		line++
-	case `"`:
-		str, err := readString(index)
-		if err != nil {
-			exitCode = 65
-			return token, err
-		} else {
-			token.setToken(STRING, `"`+str+`"`, str)
+// parseFlags pulls the --name=value flags out of args, wherever they appear,
+// and returns the remaining arguments.
+func parseFlags(args []string) (options, []string) {
+	opts := options{
+		format:      "text",
+		errorFormat: "text",
+	}
+
+	rest := []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "-e" && i+1 < len(args) {
+			// the inline source is taken as is, even if it looks like a flag
+			rest = append(rest, arg, args[i+1])
+			i++
+			continue
 		}
-	default:
-		if isStringDigit(ch) {
-			str, frac := readNumber(index)
-			if frac == "0" {
-				floatVal, _ := strconv.ParseFloat(str, 64)
-				intVal := int64(floatVal)
-				token.setToken(NUMBER, str, strconv.FormatInt(intVal, 10)+"."+frac)
-			} else {
-				token.setToken(NUMBER, str, strings.Trim(str, "0"))
+
+		if value, ok := strings.CutPrefix(arg, "--format="); ok {
+			if value != "text" && value != "json" {
+				fmt.Fprintf(os.Stderr, "Unknown format: %s\n", value)
+				usage()
 			}
-			*index--
-		} else if isIndentifierStart(ch) {
-			str := readIdentifier(index)
-			if _, isKeyword := keywords[str]; isKeyword {
-				token.setToken(keywords[str], str)
-			} else {
-				token.setToken(IDENTIFIER, str)
+			opts.format = value
+		} else if arg == "--unicode-identifiers" {
+			opts.unicodeIdentifiers = true
+		} else if value, ok := strings.CutPrefix(arg, "--error-format="); ok {
+			if value != "text" && value != "json" {
+				fmt.Fprintf(os.Stderr, "Unknown error format: %s\n", value)
+				usage()
 			}
-			*index--
+			opts.errorFormat = value
 		} else {
-			exitCode = 65
-			return token, fmt.Errorf("Error: Unexpected character: %s", ch)
+			rest = append(rest, arg)
 		}
 	}
 
-	return token, nil
-}
-
-func nextToken(index int) string {
-	if index < len(fileContentString)-1 {
-		return string(fileContentString[index+1])
-	}
-	return ""
+	return opts, rest
 }
 
-func readString(index *int) (string, error) {
-	j := *index + 1
-	for j < len(fileContentString) && fileContentString[j] != '"' {
-		j++
-	}
-
-	if j < len(fileContentString) && fileContentString[j] == '"' {
-		str := fileContentString[*index+1 : j]
-		*index = j
-		return str, nil
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
 	}
 
-	*index = j
-	return "", fmt.Errorf("Error: Unterminated string.")
+	return info.Mode()&os.ModeCharDevice != 0
 }
 
-func readNumber(index *int) (string, string) {
-	str := ""
-	frac := ""
-	for *index < len(fileContentString) && isStringDigit(string(fileContentString[*index])) {
-		str += string(fileContentString[*index])
-		*index++
-	}
-
-	if *index < len(fileContentString)-1 && fileContentString[*index] == '.' && isStringDigit(string(fileContentString[*index+1])) {
-		str += "."
-		*index++
-		for *index < len(fileContentString) && isStringDigit(string(fileContentString[*index])) {
-			str += string(fileContentString[*index])
-			frac += string(fileContentString[*index])
-			*index++
-		}
-	}
-
-	if i, _ := strconv.Atoi(frac); i == 0 {
-		frac = "0"
+func printJSON(data []byte, err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		os.Exit(1)
 	}
 
-	return str, frac
+	fmt.Println(string(data))
 }
 
-func readIdentifier(index *int) string {
-	str := ""
-	for *index < len(fileContentString) && isAlphaNum(string(fileContentString[*index])) {
-		str += string(fileContentString[*index])
-		*index++
-	}
-
-	return str
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: ./your_program.sh <tokenize|parse|evaluate|run> [--format=text|json] [--error-format=text|json] [--unicode-identifiers] <filename | - | -e source>")
+	fmt.Fprintln(os.Stderr, "       ./your_program.sh [repl]")
+	os.Exit(1)
 }
 
-func isStringDigit(s string) bool {
-	if len(s) == 1 && s >= "0" && s <= "9" {
-		return true
+// readSource returns the program named by the command's arguments: a file
+// name, "-" for stdin, or "-e" followed by the source itself.
+func readSource(args []string) (string, error) {
+	switch args[0] {
+	case "-e":
+		if len(args) < 2 {
+			usage()
+		}
+		return args[1], nil
+	case "-":
+		contents, err := io.ReadAll(os.Stdin)
+		return string(contents), err
+	default:
+		contents, err := os.ReadFile(args[0])
+		return string(contents), err
 	}
-	return false
-}
-
-func isIndentifierStart(s string) bool {
-	return len(s) == 1 && (s >= "a" && s <= "z") || (s >= "A" && s <= "Z") || (s == "_")
-}
-
-func isAlphaNum(s string) bool {
-	return isIndentifierStart(s) || isStringDigit(s)
 }
 
-func getStringType(s string) string {
-	if _, err := strconv.ParseFloat(s, 64); err == nil {
-		return "number"
-	} else if s == "true" || s == "false" {
-		return "bool"
-	} else if s == "nil" {
-		return "nil"
-	} else {
-		return "string"
+// exitCode maps an interpreter error to the process exit status: 65 for
+// syntax errors and 70 for runtime errors.
+func exitCode(err error) int {
+	var syntaxErr *lox.SyntaxError
+	var runtimeErr *lox.RuntimeError
+
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &syntaxErr):
+		return 65
+	case errors.As(err, &runtimeErr):
+		return 70
+	default:
+		return 1
 	}
 }
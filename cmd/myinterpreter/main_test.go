@@ -0,0 +1,86 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/codecrafters-io/interpreter-starter-go/lox"
+)
+
+func TestReadSource(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "prog.lox")
+	if err := os.WriteFile(file, []byte("print 1;"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdin := filepath.Join(dir, "stdin")
+	if err := os.WriteFile(stdin, []byte("print 2;"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	in, err := os.Open(stdin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	saved := os.Stdin
+	os.Stdin = in
+	defer func() { os.Stdin = saved }()
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"file", []string{file}, "print 1;"},
+		{"stdin", []string{"-"}, "print 2;"},
+		{"inline", []string{"-e", "print 3;"}, "print 3;"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := readSource(test.args)
+			if err != nil {
+				t.Fatalf("readSource returned %v", err)
+			}
+			if got != test.want {
+				t.Errorf("readSource = %q, want %q", got, test.want)
+			}
+		})
+	}
+
+	if _, err := readSource([]string{filepath.Join(dir, "missing.lox")}); err == nil {
+		t.Error("readSource of a missing file returned no error")
+	}
+}
+
+func TestParseFlagsKeepsInlineSource(t *testing.T) {
+	opts, rest := parseFlags([]string{"--format=json", "-e", "--format=text"})
+	if opts.format != "json" {
+		t.Errorf("format = %q, want json", opts.format)
+	}
+	if want := []string{"-e", "--format=text"}; !reflect.DeepEqual(rest, want) {
+		t.Errorf("rest = %q, want %q", rest, want)
+	}
+}
+
+func TestWithComments(t *testing.T) {
+	interpreter := lox.New(lox.Options{Stdout: io.Discard, Stderr: io.Discard})
+	source := "// a\nprint /* b */ 1; // c"
+	tokens, err := interpreter.Tokenize(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := []string{}
+	for _, token := range withComments(tokens, interpreter.Comments(source)) {
+		got = append(got, token.Lexeme())
+	}
+	want := []string{"// a", "print", "/* b */", "1", ";", "// c", ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("lexemes = %q, want %q", got, want)
+	}
+}
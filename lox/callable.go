@@ -0,0 +1,103 @@
+package lox
+
+import (
+	"fmt"
+	"time"
+)
+
+// Callable is a Lox value that can be called: user functions and natives.
+type Callable interface {
+	Arity() int
+	Call(interpreter *Interpreter, args []Value) (Value, error)
+	String() string
+}
+
+// Function is a function declared in Lox, closing over the scope it was
+// declared in.
+type Function struct {
+	declaration   *FunctionStmt
+	closure       *Scope
+	isInitializer bool
+}
+
+func (f *Function) Arity() int {
+	return len(f.declaration.params)
+}
+
+func (f *Function) Call(interpreter *Interpreter, args []Value) (Value, error) {
+	scope := NewScope(f.closure)
+	for i, param := range f.declaration.params {
+		scope.setScopeValue(param.lexeme, args[i])
+	}
+
+	err := executeBlock(interpreter, f.declaration.body, scope)
+	ret, isReturn := err.(*returnValue)
+	if err != nil && !isReturn {
+		return nil, err
+	}
+
+	// an initializer always returns the instance, even from a bare return
+	if f.isInitializer {
+		this, _ := f.closure.getScopeValue("this")
+		return this, nil
+	}
+	if isReturn {
+		return ret.value, nil
+	}
+
+	return nil, nil
+}
+
+// bind returns a copy of the method whose closure defines "this" as instance.
+func (f *Function) bind(instance *Instance) *Function {
+	scope := NewScope(f.closure)
+	scope.setScopeValue("this", instance)
+
+	return &Function{
+		declaration:   f.declaration,
+		closure:       scope,
+		isInitializer: f.isInitializer,
+	}
+}
+
+func (f *Function) String() string {
+	return fmt.Sprintf("<fn %s>", f.declaration.name.lexeme)
+}
+
+// NativeFunction is a function implemented in Go. Embedders can expose one to
+// Lox code with Interpreter.SetGlobal.
+type NativeFunction struct {
+	Name   string
+	Params int
+	Fn     func(args []Value) (Value, error)
+}
+
+func (n *NativeFunction) Arity() int {
+	return n.Params
+}
+
+func (n *NativeFunction) Call(interpreter *Interpreter, args []Value) (Value, error) {
+	return n.Fn(args)
+}
+
+func (n *NativeFunction) String() string {
+	return "<native fn>"
+}
+
+// returnValue unwinds a function body from a return statement. It travels up
+// through Execute as an error and is caught by Function.Call.
+type returnValue struct {
+	value Value
+}
+
+func (r *returnValue) Error() string {
+	return "return outside of a function"
+}
+
+var clock = &NativeFunction{
+	Name:   "clock",
+	Params: 0,
+	Fn: func(args []Value) (Value, error) {
+		return float64(time.Now().UnixNano()) / float64(time.Second), nil
+	},
+}
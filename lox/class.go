@@ -0,0 +1,80 @@
+package lox
+
+import (
+	"fmt"
+)
+
+// Class is a Lox class. Calling it creates a new Instance and runs the
+// class's init method, if it has one, with the call's arguments.
+type Class struct {
+	name       string
+	superclass *Class
+	methods    map[string]*Function
+}
+
+// findMethod looks name up on the class and then up its superclass chain.
+func (c *Class) findMethod(name string) *Function {
+	if method, ok := c.methods[name]; ok {
+		return method
+	}
+
+	if c.superclass != nil {
+		return c.superclass.findMethod(name)
+	}
+
+	return nil
+}
+
+func (c *Class) Arity() int {
+	if initializer := c.findMethod("init"); initializer != nil {
+		return initializer.Arity()
+	}
+
+	return 0
+}
+
+func (c *Class) Call(interpreter *Interpreter, args []Value) (Value, error) {
+	instance := &Instance{
+		class:  c,
+		fields: make(map[string]Value),
+	}
+
+	if initializer := c.findMethod("init"); initializer != nil {
+		if _, err := initializer.bind(instance).Call(interpreter, args); err != nil {
+			return nil, err
+		}
+	}
+
+	return instance, nil
+}
+
+func (c *Class) String() string {
+	return c.name
+}
+
+// Instance is an object created by calling a Class.
+type Instance struct {
+	class  *Class
+	fields map[string]Value
+}
+
+// get returns a field or, failing that, a method bound to the instance.
+func (i *Instance) get(name Token) (Value, error) {
+	if val, ok := i.fields[name.lexeme]; ok {
+		return val, nil
+	}
+
+	if method := i.class.findMethod(name.lexeme); method != nil {
+		return method.bind(i), nil
+	}
+
+	return nil, newRuntimeError(name, "undefined-property", fmt.Sprintf("Undefined property '%s'.", name.lexeme))
+}
+
+func (i *Instance) set(name Token, val Value) {
+	i.fields[name.lexeme] = val
+}
+
+func (i *Instance) String() string {
+	return fmt.Sprintf("%s instance", i.class.name)
+}
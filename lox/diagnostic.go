@@ -0,0 +1,101 @@
+package lox
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type Severity string
+
+const (
+	SeverityError Severity = "error"
+)
+
+// Phase is the stage of the interpreter that found a problem.
+type Phase string
+
+const (
+	PhaseLex     Phase = "lex"
+	PhaseParse   Phase = "parse"
+	PhaseResolve Phase = "resolve"
+	PhaseRuntime Phase = "runtime"
+)
+
+// Diagnostic is a problem found in a program. Code is a short stable name for
+// the kind of problem, e.g. "undefined-variable", that tools can match on.
+type Diagnostic struct {
+	Severity Severity
+	Code     string
+	Phase    Phase
+	Message  string
+	Span     Span
+
+	// line is the line shown in the text format, where shows the offending
+	// token, e.g. " at 'x'".
+	line  int
+	where string
+}
+
+func newDiagnostic(phase Phase, code string, span Span, message string) *Diagnostic {
+	return &Diagnostic{
+		Severity: SeverityError,
+		Code:     code,
+		Phase:    phase,
+		Message:  message,
+		Span:     span,
+		line:     span.start.line,
+	}
+}
+
+// newTokenDiagnostic reports a problem at token, naming the token in the
+// text format.
+func newTokenDiagnostic(phase Phase, code string, token Token, message string) *Diagnostic {
+	if token.TokenType == INTERPOLATION_MIDDLE || token.TokenType == INTERPOLATION_END {
+		token = token.closingBrace()
+	}
+	diagnostic := newDiagnostic(phase, code, token.span(), message)
+	if token.TokenType == EOF {
+		diagnostic.where = " at end"
+	} else {
+		diagnostic.where = fmt.Sprintf(" at '%s'", token.lexeme)
+	}
+
+	return diagnostic
+}
+
+// Error renders the diagnostic in the text format of the reference Lox
+// implementation.
+func (d *Diagnostic) Error() string {
+	if d.Phase == PhaseRuntime {
+		return fmt.Sprintf("%s\n[line %d]", d.Message, d.line)
+	}
+
+	return fmt.Sprintf("[line %d] Error%s: %s", d.line, d.where, d.Message)
+}
+
+type jsonDiagnostic struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Phase    Phase    `json:"phase"`
+	Message  string   `json:"message"`
+	Span     jsonSpan `json:"span"`
+}
+
+func (d *Diagnostic) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonDiagnostic{
+		Severity: d.Severity,
+		Code:     d.Code,
+		Phase:    d.Phase,
+		Message:  d.Message,
+		Span:     d.Span.toJSON(),
+	})
+}
+
+// Line, Column and Offset expose a position to embedders.
+func (position Position) Line() int   { return position.line }
+func (position Position) Column() int { return position.column }
+func (position Position) Offset() int { return position.offset }
+
+// Start and End expose a span to embedders.
+func (span Span) Start() Position { return span.start }
+func (span Span) End() Position   { return span.end }
@@ -0,0 +1,75 @@
+package lox
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJSONDiagnostics(t *testing.T) {
+	tests := []struct {
+		source string
+		phase  Phase
+		code   string
+		line   int
+		column int
+	}{
+		{"print 1; @", PhaseLex, "unexpected-character", 1, 10},
+		{"print 1 +;", PhaseParse, "expect-expression", 1, 10},
+		{"return 1;", PhaseResolve, "top-level-return", 1, 1},
+		{"print 1;\nprint -nil;", PhaseRuntime, "operand-type", 2, 7},
+	}
+
+	for _, test := range tests {
+		var stderr bytes.Buffer
+		interpreter := New(Options{Stdout: &bytes.Buffer{}, Stderr: &stderr, ErrorFormat: "json"})
+		interpreter.Run(test.source)
+
+		lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+		if len(lines) != 1 {
+			t.Errorf("%q: got %d lines of output, want 1: %q", test.source, len(lines), stderr.String())
+			continue
+		}
+		var got struct {
+			Severity string   `json:"severity"`
+			Code     string   `json:"code"`
+			Phase    string   `json:"phase"`
+			Message  string   `json:"message"`
+			Span     jsonSpan `json:"span"`
+		}
+		if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+			t.Errorf("%q: output %q is not JSON: %v", test.source, lines[0], err)
+			continue
+		}
+		if got.Severity != string(SeverityError) || got.Phase != string(test.phase) || got.Code != test.code || got.Message == "" {
+			t.Errorf("%q: got %+v, want a %s error in phase %s", test.source, got, test.code, test.phase)
+		}
+		if start := got.Span.Start; start.Line != test.line || start.Column != test.column {
+			t.Errorf("%q: span starts at %d:%d, want %d:%d", test.source, start.Line, start.Column, test.line, test.column)
+		}
+	}
+}
+
+func TestSyntaxErrorDiagnostics(t *testing.T) {
+	interpreter := New(Options{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}})
+	err := interpreter.Run("print 1; @\nvar = 1;")
+
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("Run returned %v, want a *SyntaxError", err)
+	}
+	want := []string{
+		"[line 1] Error: Unexpected character: @",
+		"[line 2] Error at '=': Expect variable name.",
+	}
+	if len(syntaxErr.Diagnostics) != len(want) {
+		t.Fatalf("got %d diagnostics, want %d", len(syntaxErr.Diagnostics), len(want))
+	}
+	for i, diagnostic := range syntaxErr.Diagnostics {
+		if diagnostic.Error() != want[i] {
+			t.Errorf("diagnostic %d = %q, want %q", i, diagnostic.Error(), want[i])
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package lox
+
+import (
+	"strings"
+)
+
+// SyntaxError is returned when the source could not be scanned, parsed or
+// resolved. It holds every problem that was found.
+type SyntaxError struct {
+	Diagnostics []*Diagnostic
+}
+
+func (e *SyntaxError) Error() string {
+	msgs := make([]string, len(e.Diagnostics))
+	for i, diagnostic := range e.Diagnostics {
+		msgs[i] = diagnostic.Error()
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// RuntimeError is returned when a program fails while executing. It is
+// reported against the token whose evaluation failed.
+type RuntimeError struct {
+	diagnostic *Diagnostic
+}
+
+func newRuntimeError(token Token, code string, message string) *RuntimeError {
+	return &RuntimeError{
+		diagnostic: newTokenDiagnostic(PhaseRuntime, code, token, message),
+	}
+}
+
+func (e *RuntimeError) Error() string {
+	return e.diagnostic.Error()
+}
+
+// Diagnostic describes the error in structured form.
+func (e *RuntimeError) Diagnostic() *Diagnostic {
+	return e.diagnostic
+}
+
+// Line is the source line the error was raised on.
+func (e *RuntimeError) Line() int {
+	return e.diagnostic.Span.start.line
+}
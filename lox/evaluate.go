@@ -0,0 +1,351 @@
+package lox
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Value is a Lox runtime value: nil, float64, string, bool, a Callable or an
+// *Instance.
+type Value interface{}
+
+func (l *Literal) Evaluate(interpreter *Interpreter, scope *Scope) (Value, error) {
+	if l.t == "bool" {
+		return strconv.ParseBool(l.value)
+	} else if l.t == "nil" {
+		return nil, nil
+	} else if l.t != "number" {
+		return l.value, nil
+	} else {
+		val, err := strconv.ParseFloat(l.value, 64)
+		if err != nil {
+			return nil, err
+		}
+		return val, nil
+	}
+}
+
+func (u *Unary) Evaluate(interpreter *Interpreter, scope *Scope) (Value, error) {
+	val, err := u.right.Evaluate(interpreter, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.operator.TokenType {
+	case MINUS:
+		if valFloat, ok := val.(float64); ok {
+			val = -valFloat
+		} else {
+			return nil, newRuntimeError(u.operator, "operand-type", "Operand must be a number.")
+		}
+	case BANG:
+		val = !isTruthy(val)
+	}
+
+	return val, nil
+}
+
+func (b *Binary) Evaluate(interpreter *Interpreter, scope *Scope) (Value, error) {
+	leftVal, err := b.left.Evaluate(interpreter, scope)
+	if err != nil {
+		return nil, err
+	}
+	rightVal, err := b.right.Evaluate(interpreter, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	switch b.operator.TokenType {
+	case PLUS:
+		return add(b.operator, leftVal, rightVal)
+	case MINUS:
+		err := checkBothNumber(b.operator, leftVal, rightVal)
+		if err != nil {
+			return nil, err
+		}
+		return leftVal.(float64) - rightVal.(float64), nil
+	case STAR:
+		err := checkBothNumber(b.operator, leftVal, rightVal)
+		if err != nil {
+			return nil, err
+		}
+		return leftVal.(float64) * rightVal.(float64), nil
+	case SLASH:
+		err := checkBothNumber(b.operator, leftVal, rightVal)
+		if err != nil {
+			return nil, err
+		}
+		return leftVal.(float64) / rightVal.(float64), nil
+	case GREATER:
+		err := checkBothNumber(b.operator, leftVal, rightVal)
+		if err != nil {
+			return nil, err
+		}
+		return leftVal.(float64) > rightVal.(float64), nil
+	case GREATER_EQUAL:
+		err := checkBothNumber(b.operator, leftVal, rightVal)
+		if err != nil {
+			return nil, err
+		}
+		return leftVal.(float64) >= rightVal.(float64), nil
+	case LESS:
+		err := checkBothNumber(b.operator, leftVal, rightVal)
+		if err != nil {
+			return nil, err
+		}
+		return leftVal.(float64) < rightVal.(float64), nil
+	case LESS_EQUAL:
+		err := checkBothNumber(b.operator, leftVal, rightVal)
+		if err != nil {
+			return nil, err
+		}
+		return leftVal.(float64) <= rightVal.(float64), nil
+	case EQUAL_EQUAL:
+		return checkEqual(leftVal, rightVal), nil
+	case BANG_EQUAL:
+		return !checkEqual(leftVal, rightVal), nil
+	default:
+		return nil, newRuntimeError(b.operator, "unknown-operator", fmt.Sprintf("Unknown operator '%s'.", b.operator.lexeme))
+	}
+}
+
+// Evaluate short-circuits: the right operand is only evaluated when the left
+// one doesn't decide the result, and the deciding operand itself is returned.
+func (l *Logical) Evaluate(interpreter *Interpreter, scope *Scope) (Value, error) {
+	left, err := l.left.Evaluate(interpreter, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.operator.TokenType == OR {
+		if isTruthy(left) {
+			return left, nil
+		}
+	} else if !isTruthy(left) {
+		return left, nil
+	}
+
+	return l.right.Evaluate(interpreter, scope)
+}
+
+func (c *Call) Evaluate(interpreter *Interpreter, scope *Scope) (Value, error) {
+	callee, err := c.callee.Evaluate(interpreter, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]Value, 0, len(c.arguments))
+	for _, argument := range c.arguments {
+		arg, err := argument.Evaluate(interpreter, scope)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+
+	function, ok := callee.(Callable)
+	if !ok {
+		return nil, newRuntimeError(c.paren, "not-callable", "Can only call functions and classes.")
+	}
+	if len(args) != function.Arity() {
+		return nil, newRuntimeError(c.paren, "arity-mismatch", fmt.Sprintf("Expected %d arguments but got %d.", function.Arity(), len(args)))
+	}
+
+	val, err := function.Call(interpreter, args)
+	if _, ok := err.(*RuntimeError); err != nil && !ok {
+		// errors from native functions are reported at the call
+		return nil, newRuntimeError(c.paren, "native-error", err.Error())
+	}
+
+	return val, err
+}
+
+func (g *Get) Evaluate(interpreter *Interpreter, scope *Scope) (Value, error) {
+	object, err := g.object.Evaluate(interpreter, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, ok := object.(*Instance)
+	if !ok {
+		return nil, newRuntimeError(g.name, "not-an-instance", "Only instances have properties.")
+	}
+
+	return instance.get(g.name)
+}
+
+func (s *Set) Evaluate(interpreter *Interpreter, scope *Scope) (Value, error) {
+	object, err := s.object.Evaluate(interpreter, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, ok := object.(*Instance)
+	if !ok {
+		return nil, newRuntimeError(s.name, "not-an-instance", "Only instances have fields.")
+	}
+
+	value, err := s.value.Evaluate(interpreter, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	instance.set(s.name, value)
+	return value, nil
+}
+
+func (t *This) Evaluate(interpreter *Interpreter, scope *Scope) (Value, error) {
+	return interpreter.lookUpVariable(scope, t.keyword, t.binding)
+}
+
+func (s *Super) Evaluate(interpreter *Interpreter, scope *Scope) (Value, error) {
+	distance := s.depth
+	superclass := scope.getScopeValueAt(distance, "super")
+	// "this" is bound in the scope just inside the one holding "super"
+	this := scope.getScopeValueAt(distance-1, "this")
+
+	method := superclass.(*Class).findMethod(s.method.lexeme)
+	if method == nil {
+		return nil, newRuntimeError(s.method, "undefined-property", fmt.Sprintf("Undefined property '%s'.", s.method.lexeme))
+	}
+
+	return method.bind(this.(*Instance)), nil
+}
+
+// Evaluate joins the parts of the string, printing each value the way print
+// would.
+func (i *Interpolation) Evaluate(interpreter *Interpreter, scope *Scope) (Value, error) {
+	var str strings.Builder
+	for _, part := range i.parts {
+		val, err := part.Evaluate(interpreter, scope)
+		if err != nil {
+			return nil, err
+		}
+		str.WriteString(Stringify(val))
+	}
+
+	return str.String(), nil
+}
+
+func (g *Grouping) Evaluate(interpreter *Interpreter, scope *Scope) (Value, error) {
+	return g.expression.Evaluate(interpreter, scope)
+}
+
+func (v *Variable) Evaluate(interpreter *Interpreter, scope *Scope) (Value, error) {
+	return interpreter.lookUpVariable(scope, v.name, v.binding)
+}
+
+func (a *Assign) Evaluate(interpreter *Interpreter, scope *Scope) (Value, error) {
+	value, err := a.value.Evaluate(interpreter, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.local {
+		scope.assignScopeValueAt(a.depth, a.name.lexeme, value)
+	} else if !interpreter.globals.assignScopeValue(a.name.lexeme, value) {
+		return nil, newRuntimeError(a.name, "undefined-variable", fmt.Sprintf("Undefined variable '%s'.", a.name.lexeme))
+	}
+
+	return value, nil
+}
+
+// Stringify renders a value the way Lox's print statement does. It is the
+// one formatter used for print, the evaluate command and the REPL.
+func Stringify(val Value) string {
+	switch val := val.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return strconv.FormatBool(val)
+	case float64:
+		return formatNumber(val)
+	case string:
+		return val
+	case fmt.Stringer:
+		// functions, classes and instances
+		return val.String()
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// formatNumber prints integral numbers without a fractional part and never
+// switches to exponent notation, so 10.0 prints as 10 and 1e21 in full.
+func formatNumber(val float64) string {
+	switch {
+	case math.IsNaN(val):
+		return "NaN"
+	case math.IsInf(val, 1):
+		return "Infinity"
+	case math.IsInf(val, -1):
+		return "-Infinity"
+	default:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	}
+}
+
+// isTruthy follows Lox: nil and false are falsey, everything else is truthy.
+func isTruthy(val Value) bool {
+	switch val := val.(type) {
+	case nil:
+		return false
+	case bool:
+		return val
+	default:
+		return true
+	}
+}
+
+func add(operator Token, left Value, right Value) (Value, error) {
+	switch left := left.(type) {
+	case float64:
+		if right, ok := right.(float64); ok {
+			return left + right, nil
+		}
+	case string:
+		if right, ok := right.(string); ok {
+			return left + right, nil
+		}
+	}
+
+	return nil, newRuntimeError(operator, "operand-type", "Operands must be two numbers or two strings.")
+}
+
+// checkEqual compares values without coercion: values of different types are
+// never equal, nil equals only nil, and functions, classes and instances are
+// equal only to themselves. Values of any other Go type, which an embedder can
+// pass in through SetGlobal or a NativeFunction, are never equal, since they
+// may not be comparable at all.
+func checkEqual(leftVal Value, rightVal Value) bool {
+	switch left := leftVal.(type) {
+	case nil:
+		return rightVal == nil
+	case bool:
+		right, ok := rightVal.(bool)
+		return ok && left == right
+	case float64:
+		right, ok := rightVal.(float64)
+		return ok && left == right
+	case string:
+		right, ok := rightVal.(string)
+		return ok && left == right
+	case *Function, *Class, *NativeFunction, *Instance:
+		// pointers, so this compares identity
+		return leftVal == rightVal
+	default:
+		return false
+	}
+}
+
+func checkBothNumber(operator Token, leftVal Value, rightVal Value) error {
+	_, leftOk := leftVal.(float64)
+	_, rightOk := rightVal.(float64)
+	if !leftOk || !rightOk {
+		return newRuntimeError(operator, "operand-type", "Operands must be numbers.")
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package lox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Options configures an Interpreter. Nil writers default to os.Stdout and
+// os.Stderr.
+type Options struct {
+	Stdout io.Writer
+	Stderr io.Writer
+	// ErrorFormat is how diagnostics are written to Stderr: "text", the
+	// default, or "json" for one JSON object per line.
+	ErrorFormat string
+	// Snippets adds the offending source line, with the problem underlined,
+	// to text diagnostics. Color highlights them with ANSI escapes.
+	Snippets bool
+	Color    bool
+	// UnicodeIdentifiers lets identifiers use any Unicode letter or digit
+	// rather than only ASCII ones.
+	UnicodeIdentifiers bool
+}
+
+// Interpreter runs Lox source against a global scope that persists across
+// calls. Program output goes to Stdout and errors are reported on Stderr; the
+// returned errors are a *SyntaxError or a *RuntimeError so callers can tell
+// which phase failed.
+type Interpreter struct {
+	globals            *Scope
+	stdout             io.Writer
+	stderr             io.Writer
+	errorFormat        string
+	snippets           bool
+	color              bool
+	unicodeIdentifiers bool
+
+	// source is the text most recently handed to the interpreter, used to
+	// show snippets in error reports.
+	source string
+}
+
+func New(opts Options) *Interpreter {
+	interpreter := &Interpreter{
+		globals:            NewScope(nil),
+		stdout:             opts.Stdout,
+		stderr:             opts.Stderr,
+		errorFormat:        opts.ErrorFormat,
+		snippets:           opts.Snippets,
+		color:              opts.Color,
+		unicodeIdentifiers: opts.UnicodeIdentifiers,
+	}
+	interpreter.globals.setScopeValue(clock.Name, clock)
+	if interpreter.stdout == nil {
+		interpreter.stdout = os.Stdout
+	}
+	if interpreter.stderr == nil {
+		interpreter.stderr = os.Stderr
+	}
+
+	return interpreter
+}
+
+// SetGlobal defines name in the global scope, replacing any previous value.
+func (interpreter *Interpreter) SetGlobal(name string, val Value) {
+	interpreter.globals.setScopeValue(name, val)
+}
+
+// Tokenize scans src into tokens ending with an EOF token.
+func (interpreter *Interpreter) Tokenize(src string) ([]Token, error) {
+	interpreter.source = src
+	tokens, errs := interpreter.scan(src)
+	if len(errs) > 0 {
+		return tokens, interpreter.syntaxError(errs...)
+	}
+
+	return tokens, nil
+}
+
+// Comments returns the comments in src as COMMENT tokens, in source order.
+// They are left out of Tokenize's output since the parser has no use for them.
+// Scan errors are ignored; Tokenize reports them.
+func (interpreter *Interpreter) Comments(src string) []Token {
+	lexer := NewLexer(src)
+	lexer.unicodeIdentifiers = interpreter.unicodeIdentifiers
+	lexer.scanTokens()
+
+	return lexer.comments
+}
+
+// Parse parses src as a single expression. Scan and parse errors are all
+// reported together.
+func (interpreter *Interpreter) Parse(src string) (Expr, error) {
+	interpreter.source = src
+	tokens, errs := interpreter.scan(src)
+
+	parser := &Parser{
+		tokens:  tokens,
+		current: 0,
+	}
+	expr, parseErrs := parser.parse()
+	errs = append(errs, parseErrs...)
+	if len(errs) > 0 {
+		return nil, interpreter.syntaxError(errs...)
+	}
+
+	return expr, nil
+}
+
+// Eval evaluates src as a single expression in the global scope.
+func (interpreter *Interpreter) Eval(src string) (Value, error) {
+	expr, err := interpreter.Parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return interpreter.evaluate(expr)
+}
+
+func (interpreter *Interpreter) evaluate(expr Expr) (Value, error) {
+	if errs := NewResolver().resolveExpression(expr); len(errs) > 0 {
+		return nil, interpreter.syntaxError(errs...)
+	}
+
+	val, err := expr.Evaluate(interpreter, interpreter.globals)
+	if err != nil {
+		return nil, interpreter.runtimeError(err)
+	}
+
+	return val, nil
+}
+
+// Run executes src as a program in the global scope. Nothing is executed
+// unless the whole program scans and parses cleanly.
+func (interpreter *Interpreter) Run(src string) error {
+	interpreter.source = src
+	tokens, errs := interpreter.scan(src)
+
+	return interpreter.run(tokens, errs)
+}
+
+func (interpreter *Interpreter) run(tokens []Token, errs []*Diagnostic) error {
+	parser := &Parser{
+		tokens:  tokens,
+		current: 0,
+	}
+	stmts, parseErrs := parser.parseStatements()
+	errs = append(errs, parseErrs...)
+	if len(errs) > 0 {
+		return interpreter.syntaxError(errs...)
+	}
+	if errs := NewResolver().resolveStatements(stmts); len(errs) > 0 {
+		return interpreter.syntaxError(errs...)
+	}
+
+	for _, stmt := range stmts {
+		if err := stmt.Execute(interpreter, interpreter.globals); err != nil {
+			return interpreter.runtimeError(err)
+		}
+	}
+
+	return nil
+}
+
+// scan tokenizes src with the interpreter's lexer settings.
+func (interpreter *Interpreter) scan(src string) ([]Token, []*Diagnostic) {
+	lexer := NewLexer(src)
+	lexer.unicodeIdentifiers = interpreter.unicodeIdentifiers
+
+	return lexer.scanTokens()
+}
+
+// lookUpVariable finds name at the depth the resolver recorded in binding, or
+// in the globals if it wasn't resolved to a local.
+func (interpreter *Interpreter) lookUpVariable(scope *Scope, name Token, binding binding) (Value, error) {
+	if binding.local {
+		return scope.getScopeValueAt(binding.depth, name.lexeme), nil
+	}
+
+	val, ok := interpreter.globals.getScopeValue(name.lexeme)
+	if !ok {
+		return nil, newRuntimeError(name, "undefined-variable", fmt.Sprintf("Undefined variable '%s'.", name.lexeme))
+	}
+
+	return val, nil
+}
+
+func (interpreter *Interpreter) syntaxError(diagnostics ...*Diagnostic) error {
+	for _, diagnostic := range diagnostics {
+		interpreter.report(diagnostic)
+	}
+
+	return &SyntaxError{Diagnostics: diagnostics}
+}
+
+func (interpreter *Interpreter) runtimeError(err error) error {
+	var runtimeErr *RuntimeError
+	if errors.As(err, &runtimeErr) {
+		interpreter.report(runtimeErr.diagnostic)
+	} else {
+		fmt.Fprintln(interpreter.stderr, err)
+	}
+
+	return err
+}
+
+// report writes a diagnostic to Stderr in the configured error format.
+func (interpreter *Interpreter) report(diagnostic *Diagnostic) {
+	if interpreter.errorFormat == "json" {
+		data, err := json.Marshal(diagnostic)
+		if err == nil {
+			fmt.Fprintln(interpreter.stderr, string(data))
+			return
+		}
+	}
+
+	if !interpreter.snippets {
+		fmt.Fprintln(interpreter.stderr, diagnostic)
+		return
+	}
+
+	header := diagnostic.Error()
+	if interpreter.color {
+		header = colorBold + header + colorReset
+	}
+	fmt.Fprintln(interpreter.stderr, header)
+	fmt.Fprint(interpreter.stderr, renderSnippet(interpreter.source, diagnostic.Span, diagnostic.line, interpreter.color))
+}
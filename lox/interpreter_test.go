@@ -0,0 +1,234 @@
+package lox
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// runOutput runs source in a fresh interpreter and returns what it printed,
+// failing the test if it doesn't run cleanly.
+func runOutput(t *testing.T, source string) string {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	interpreter := New(Options{Stdout: &stdout, Stderr: &stderr})
+	if err := interpreter.Run(source); err != nil {
+		t.Fatalf("Run(%q) returned %v: %s", source, err, stderr.String())
+	}
+
+	return stdout.String()
+}
+
+func TestInterpolation(t *testing.T) {
+	source := `
+var name = "Ada";
+var count = 3;
+class A {}
+print "Hello ${name}, you have ${count} items";
+print "${count + 1.5}${nil}${true} ${A()} ${clock}";
+print "nested ${"inner ${name + "!"}"} done";
+print "\${raw}";`
+	want := "Hello Ada, you have 3 items\n4.5niltrue A instance <native fn>\nnested inner Ada! done\n${raw}\n"
+	if got := runOutput(t, source); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestSetGlobal(t *testing.T) {
+	interpreter := New(Options{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}})
+	interpreter.SetGlobal("answer", 42.0)
+	interpreter.SetGlobal("twice", &NativeFunction{
+		Name:   "twice",
+		Params: 1,
+		Fn: func(args []Value) (Value, error) {
+			return args[0].(float64) * 2, nil
+		},
+	})
+
+	got, err := interpreter.Eval("twice(answer)")
+	if err != nil {
+		t.Fatalf("Eval returned %v", err)
+	}
+	if got != 84.0 {
+		t.Errorf("Eval = %v, want 84", got)
+	}
+}
+
+func TestGlobalsPersist(t *testing.T) {
+	var stdout bytes.Buffer
+	interpreter := New(Options{Stdout: &stdout, Stderr: &bytes.Buffer{}})
+	for _, source := range []string{"var greeting = \"hi\";", "print greeting;"} {
+		if err := interpreter.Run(source); err != nil {
+			t.Fatalf("Run(%q) returned %v", source, err)
+		}
+	}
+
+	if got := stdout.String(); got != "hi\n" {
+		t.Errorf("output = %q, want %q", got, "hi\n")
+	}
+}
+
+func TestErrorTypes(t *testing.T) {
+	var stderr bytes.Buffer
+	interpreter := New(Options{Stdout: &bytes.Buffer{}, Stderr: &stderr})
+
+	var syntaxErr *SyntaxError
+	if err := interpreter.Run("print 1 +;"); !errors.As(err, &syntaxErr) {
+		t.Errorf("Run returned %v, want a *SyntaxError", err)
+	}
+
+	var runtimeErr *RuntimeError
+	if err := interpreter.Run("print -nil;"); !errors.As(err, &runtimeErr) {
+		t.Errorf("Run returned %v, want a *RuntimeError", err)
+	}
+
+	if stderr.Len() == 0 {
+		t.Error("errors were not reported on Stderr")
+	}
+}
+
+func TestRuntimeErrorLine(t *testing.T) {
+	interpreter := New(Options{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}})
+	err := interpreter.Run("print 1;\nprint 2 *\n  nil;")
+
+	var runtimeErr *RuntimeError
+	if !errors.As(err, &runtimeErr) {
+		t.Fatalf("Run returned %v, want a *RuntimeError", err)
+	}
+	// the operator's line, not the operand's
+	if runtimeErr.Line() != 2 {
+		t.Errorf("runtime error on line %d, want 2", runtimeErr.Line())
+	}
+}
+
+func TestControlFlow(t *testing.T) {
+	source := `
+for (var i = 0; i < 3; i = i + 1) print i;
+var n = 0;
+while (n < 2) n = n + 1;
+if (n == 2) print "two"; else print "other";`
+	if got, want := runOutput(t, source), "0\n1\n2\ntwo\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestFunctions(t *testing.T) {
+	source := `
+fun fib(n) {
+  if (n < 2) return n;
+  return fib(n - 1) + fib(n - 2);
+}
+print fib(10);
+fun makeAdder(x) {
+  fun add(y) { return x + y; }
+  return add;
+}
+print makeAdder(1)(2);
+fun nothing() {}
+print nothing();`
+	if got, want := runOutput(t, source), "55\n3\nnil\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestClasses(t *testing.T) {
+	source := `
+class A {
+  greet() { return "A"; }
+}
+class B < A {
+  init(name) { this.name = name; }
+  greet() { return super.greet() + this.name; }
+}
+var b = B("b");
+print b.greet();
+b.extra = 1;
+print b.extra;
+print B;
+print b;`
+	if got, want := runOutput(t, source), "Ab\n1\nB\nB instance\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestAssignUndefined(t *testing.T) {
+	var stderr bytes.Buffer
+	interpreter := New(Options{Stdout: &bytes.Buffer{}, Stderr: &stderr})
+	err := interpreter.Run("x = 1;")
+
+	var runtimeErr *RuntimeError
+	if !errors.As(err, &runtimeErr) {
+		t.Fatalf("Run returned %v, want a *RuntimeError", err)
+	}
+	if got, want := stderr.String(), "Undefined variable 'x'.\n[line 1]\n"; got != want {
+		t.Errorf("stderr = %q, want %q", got, want)
+	}
+	if _, err := interpreter.Eval("x"); err == nil {
+		t.Error("the failed assignment defined x")
+	}
+}
+
+func TestAssignNearestScope(t *testing.T) {
+	source := `
+var a = "global";
+{
+  var a = "outer";
+  {
+    a = "assigned";
+  }
+  print a;
+}
+print a;`
+	if got, want := runOutput(t, source), "assigned\nglobal\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestEquality(t *testing.T) {
+	interpreter := New(Options{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}})
+	// host values Lox can't compare must not panic on ==
+	interpreter.SetGlobal("xs", []int{1})
+	interpreter.SetGlobal("m", map[string]int{})
+	if err := interpreter.Run("class A {} var a = A(); fun f() {}"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{"nil == nil", true},
+		{"nil == false", false},
+		{"0 == false", false},
+		{`"1" == 1`, false},
+		{`"a" + "b" == "ab"`, true},
+		{"a == a", true},
+		{"a == A()", false},
+		{"f == f", true},
+		{"clock == clock", true},
+		{"xs == xs", false},
+		{"xs != m", true},
+		{"xs == nil", false},
+	}
+
+	for _, test := range tests {
+		got, err := interpreter.Eval(test.source)
+		if err != nil {
+			t.Errorf("Eval(%q) returned %v", test.source, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("Eval(%q) = %v, want %v", test.source, got, test.want)
+		}
+	}
+}
+
+func TestTruthiness(t *testing.T) {
+	source := `
+if (0) print "0"; if ("") print "empty";
+if (nil) print "nil"; if (false) print "false";
+print !nil; print !0;`
+	if got, want := runOutput(t, source), "0\nempty\ntrue\nfalse\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
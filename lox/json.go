@@ -0,0 +1,246 @@
+package lox
+
+import (
+	"encoding/json"
+)
+
+// jsonNode is the JSON shape of an expression node. Fields that don't apply
+// to a node kind are left out.
+type jsonNode struct {
+	Kind     string     `json:"kind"`
+	Span     jsonSpan   `json:"span"`
+	Type     string     `json:"type,omitempty"`
+	Value    *string    `json:"value,omitempty"`
+	Operator string     `json:"operator,omitempty"`
+	Name     string     `json:"name,omitempty"`
+	Children []jsonNode `json:"children,omitempty"`
+}
+
+type jsonPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Offset int `json:"offset"`
+}
+
+type jsonSpan struct {
+	Start jsonPosition `json:"start"`
+	End   jsonPosition `json:"end"`
+}
+
+type jsonToken struct {
+	Type    string       `json:"type"`
+	Lexeme  string       `json:"lexeme"`
+	Literal *string      `json:"literal"`
+	Start   jsonPosition `json:"start"`
+	End     jsonPosition `json:"end"`
+}
+
+func (position Position) toJSON() jsonPosition {
+	return jsonPosition{
+		Line:   position.line,
+		Column: position.column,
+		Offset: position.offset,
+	}
+}
+
+func (span Span) toJSON() jsonSpan {
+	return jsonSpan{
+		Start: span.start.toJSON(),
+		End:   span.end.toJSON(),
+	}
+}
+
+// MarshalJSON encodes a token with its type, lexeme, literal (null when the
+// token has none) and source positions.
+func (token Token) MarshalJSON() ([]byte, error) {
+	var literal *string
+	if token.hasLiteral() {
+		literal = &token.literal
+	}
+
+	return json.Marshal(jsonToken{
+		Type:    token.TokenType,
+		Lexeme:  token.lexeme,
+		Literal: literal,
+		Start:   token.start.toJSON(),
+		End:     token.end.toJSON(),
+	})
+}
+
+// MarshalAST encodes an expression tree as nested JSON nodes, each with its
+// kind, source span and children.
+func MarshalAST(expr Expr) ([]byte, error) {
+	return json.MarshalIndent(expr.toJSON(), "", "  ")
+}
+
+func (a *Assign) toJSON() jsonNode {
+	return jsonNode{
+		Kind:     "Assign",
+		Span:     a.span().toJSON(),
+		Name:     a.name.lexeme,
+		Children: []jsonNode{a.value.toJSON()},
+	}
+}
+
+func (b *Binary) toJSON() jsonNode {
+	return jsonNode{
+		Kind:     "Binary",
+		Span:     b.span().toJSON(),
+		Operator: b.operator.lexeme,
+		Children: []jsonNode{b.left.toJSON(), b.right.toJSON()},
+	}
+}
+
+func (c *Call) toJSON() jsonNode {
+	children := []jsonNode{c.callee.toJSON()}
+	for _, argument := range c.arguments {
+		children = append(children, argument.toJSON())
+	}
+
+	return jsonNode{
+		Kind:     "Call",
+		Span:     c.span().toJSON(),
+		Children: children,
+	}
+}
+
+func (g *Get) toJSON() jsonNode {
+	return jsonNode{
+		Kind:     "Get",
+		Span:     g.span().toJSON(),
+		Name:     g.name.lexeme,
+		Children: []jsonNode{g.object.toJSON()},
+	}
+}
+
+func (i *Interpolation) toJSON() jsonNode {
+	children := []jsonNode{}
+	for _, part := range i.parts {
+		children = append(children, part.toJSON())
+	}
+
+	return jsonNode{
+		Kind:     "Interpolation",
+		Span:     i.span().toJSON(),
+		Children: children,
+	}
+}
+
+func (g *Grouping) toJSON() jsonNode {
+	return jsonNode{
+		Kind:     "Grouping",
+		Span:     g.span().toJSON(),
+		Children: []jsonNode{g.expression.toJSON()},
+	}
+}
+
+func (l *Literal) toJSON() jsonNode {
+	return jsonNode{
+		Kind:  "Literal",
+		Span:  l.span().toJSON(),
+		Type:  l.t,
+		Value: &l.value,
+	}
+}
+
+func (l *Logical) toJSON() jsonNode {
+	return jsonNode{
+		Kind:     "Logical",
+		Span:     l.span().toJSON(),
+		Operator: l.operator.lexeme,
+		Children: []jsonNode{l.left.toJSON(), l.right.toJSON()},
+	}
+}
+
+func (s *Set) toJSON() jsonNode {
+	return jsonNode{
+		Kind:     "Set",
+		Span:     s.span().toJSON(),
+		Name:     s.name.lexeme,
+		Children: []jsonNode{s.object.toJSON(), s.value.toJSON()},
+	}
+}
+
+func (s *Super) toJSON() jsonNode {
+	return jsonNode{
+		Kind: "Super",
+		Span: s.span().toJSON(),
+		Name: s.method.lexeme,
+	}
+}
+
+func (t *This) toJSON() jsonNode {
+	return jsonNode{
+		Kind: "This",
+		Span: t.span().toJSON(),
+	}
+}
+
+func (u *Unary) toJSON() jsonNode {
+	return jsonNode{
+		Kind:     "Unary",
+		Span:     u.span().toJSON(),
+		Operator: u.operator.lexeme,
+		Children: []jsonNode{u.right.toJSON()},
+	}
+}
+
+func (v *Variable) toJSON() jsonNode {
+	return jsonNode{
+		Kind: "Variable",
+		Span: v.span().toJSON(),
+		Name: v.name.lexeme,
+	}
+}
+
+func (a *Assign) span() Span {
+	return Span{start: a.name.start, end: a.value.span().end}
+}
+
+func (b *Binary) span() Span {
+	return Span{start: b.left.span().start, end: b.right.span().end}
+}
+
+func (c *Call) span() Span {
+	return Span{start: c.callee.span().start, end: c.paren.end}
+}
+
+func (g *Get) span() Span {
+	return Span{start: g.object.span().start, end: g.name.end}
+}
+
+func (i *Interpolation) span() Span {
+	return Span{start: i.start.start, end: i.end.end}
+}
+
+func (g *Grouping) span() Span {
+	return Span{start: g.leftParen.start, end: g.rightParen.end}
+}
+
+func (l *Literal) span() Span {
+	return l.token.span()
+}
+
+func (l *Logical) span() Span {
+	return Span{start: l.left.span().start, end: l.right.span().end}
+}
+
+func (s *Set) span() Span {
+	return Span{start: s.object.span().start, end: s.value.span().end}
+}
+
+func (s *Super) span() Span {
+	return Span{start: s.keyword.start, end: s.method.end}
+}
+
+func (t *This) span() Span {
+	return t.keyword.span()
+}
+
+func (u *Unary) span() Span {
+	return Span{start: u.operator.start, end: u.right.span().end}
+}
+
+func (v *Variable) span() Span {
+	return v.name.span()
+}
@@ -0,0 +1,63 @@
+package lox
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestTokenJSONLiteral(t *testing.T) {
+	tokens, errs := NewLexer(`"null" null 1`).scanTokens()
+	if len(errs) > 0 {
+		t.Fatalf("unexpected error %v", errs[0])
+	}
+
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []struct {
+		Type    string  `json:"type"`
+		Literal *string `json:"literal"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	// a literal is present by token type, whatever its text
+	if decoded[0].Literal == nil || *decoded[0].Literal != "null" {
+		t.Errorf("string literal = %v, want \"null\"", decoded[0].Literal)
+	}
+	if decoded[1].Literal != nil {
+		t.Errorf("keyword literal = %q, want null", *decoded[1].Literal)
+	}
+	if decoded[2].Literal == nil || *decoded[2].Literal != "1.0" {
+		t.Errorf("number literal = %v, want \"1.0\"", decoded[2].Literal)
+	}
+}
+
+func TestMarshalAST(t *testing.T) {
+	interpreter := New(Options{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}})
+	expr, err := interpreter.Parse("-a + 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := MarshalAST(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var root jsonNode
+	if err := json.Unmarshal(data, &root); err != nil {
+		t.Fatal(err)
+	}
+	if root.Kind != "Binary" || root.Operator != "+" || len(root.Children) != 2 {
+		t.Fatalf("root = %+v, want a Binary + with two children", root)
+	}
+	if unary := root.Children[0]; unary.Kind != "Unary" || unary.Children[0].Name != "a" {
+		t.Errorf("left = %+v, want -a", unary)
+	}
+	if end := root.Span.End; end.Column != 7 {
+		t.Errorf("span ends at column %d, want 7", end.Column)
+	}
+}
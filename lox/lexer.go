@@ -0,0 +1,707 @@
+package lox
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	LEFT_PAREN    = "LEFT_PAREN"
+	RIGHT_PAREN   = "RIGHT_PAREN"
+	LEFT_BRACE    = "LEFT_BRACE"
+	RIGHT_BRACE   = "RIGHT_BRACE"
+	STAR          = "STAR"
+	DOT           = "DOT"
+	COMMA         = "COMMA"
+	PLUS          = "PLUS"
+	MINUS         = "MINUS"
+	SEMICOLON     = "SEMICOLON"
+	EQUAL         = "EQUAL"
+	EQUAL_EQUAL   = "EQUAL_EQUAL"
+	BANG          = "BANG"
+	BANG_EQUAL    = "BANG_EQUAL"
+	LESS          = "LESS"
+	LESS_EQUAL    = "LESS_EQUAL"
+	GREATER       = "GREATER"
+	GREATER_EQUAL = "GREATER_EQUAL"
+	SLASH         = "SLASH"
+	STRING        = "STRING"
+	INTERPOLATION = "INTERPOLATION"
+	NUMBER        = "NUMBER"
+	IDENTIFIER    = "IDENTIFIER"
+	AND           = "AND"
+	CLASS         = "CLASS"
+	ELSE          = "ELSE"
+	FALSE         = "FALSE"
+	FOR           = "FOR"
+	FUN           = "FUN"
+	IF            = "IF"
+	NIL           = "NIL"
+	OR            = "OR"
+	PRINT         = "PRINT"
+	RETURN        = "RETURN"
+	SUPER         = "SUPER"
+	THIS          = "THIS"
+	TRUE          = "TRUE"
+	VAR           = "VAR"
+	WHILE         = "WHILE"
+	COMMENT       = "COMMENT"
+	EOF           = "EOF"
+)
+
+// The segments of an interpolated string after each interpolated expression
+// start at the } that closes it.
+const (
+	INTERPOLATION_MIDDLE = "INTERPOLATION_MIDDLE"
+	INTERPOLATION_END    = "INTERPOLATION_END"
+)
+
+var keywords = map[string]string{
+	"and":    AND,
+	"class":  CLASS,
+	"else":   ELSE,
+	"false":  FALSE,
+	"for":    FOR,
+	"fun":    FUN,
+	"if":     IF,
+	"nil":    NIL,
+	"or":     OR,
+	"print":  PRINT,
+	"return": RETURN,
+	"super":  SUPER,
+	"this":   THIS,
+	"true":   TRUE,
+	"var":    VAR,
+	"while":  WHILE,
+}
+
+// Position is a location in the source: 1-based line and column plus the
+// 0-based byte offset.
+type Position struct {
+	line   int
+	column int
+	offset int
+}
+
+// Span is the source range from start up to, but not including, end.
+type Span struct {
+	start Position
+	end   Position
+}
+
+type Token struct {
+	TokenType string
+	lexeme    string
+	literal   string
+	start     Position
+	end       Position
+}
+
+func (token *Token) setToken(args ...string) {
+	token.TokenType = args[0]
+	token.lexeme = args[1]
+	if len(args) > 2 {
+		token.literal = args[2]
+	} else {
+		token.literal = "null"
+	}
+}
+
+// hasLiteral reports whether the token type carries a literal value. Others
+// hold the text-format placeholder "null", which a string can hold too.
+func (token Token) hasLiteral() bool {
+	switch token.TokenType {
+	case STRING, INTERPOLATION, INTERPOLATION_MIDDLE, INTERPOLATION_END, NUMBER:
+		return true
+	default:
+		return false
+	}
+}
+
+// closingBrace narrows a segment that resumes an interpolated string to the }
+// it starts with. Errors about the expression before it are reported there.
+func (token Token) closingBrace() Token {
+	end := token.start
+	end.column++
+	end.offset++
+	brace := Token{start: token.start, end: end}
+	brace.setToken(RIGHT_BRACE, "}")
+	return brace
+}
+
+func (token Token) span() Span {
+	return Span{start: token.start, end: token.end}
+}
+
+// Lexeme and Span expose a token to embedders, such as a formatter reading
+// the comments returned by Comments.
+func (token Token) Lexeme() string { return token.lexeme }
+func (token Token) Span() Span     { return token.span() }
+
+func (token Token) String() string {
+	return fmt.Sprintf("%s %s %s", token.TokenType, token.lexeme, token.literal)
+}
+
+// Lexer turns a UTF-8 source string into tokens. Every token records where
+// it starts and where it ends (exclusive) in the source; columns count
+// characters while offsets count bytes.
+type Lexer struct {
+	source  string
+	start   Position
+	current Position
+
+	// unicodeIdentifiers allows any Unicode letter in identifiers, not just
+	// ASCII ones.
+	unicodeIdentifiers bool
+
+	// comments holds every comment in the source as a COMMENT token. They are
+	// trivia: the parser never sees them, but tools such as formatters do.
+	comments []Token
+
+	// interpolations holds each ${ whose closing } hasn't been reached yet.
+	// The innermost is last.
+	interpolations []openInterpolation
+}
+
+// openInterpolation is an interpolated expression being scanned.
+type openInterpolation struct {
+	// quote is where the string containing the expression opens.
+	quote Position
+	// braces counts the braces open inside the expression.
+	braces int
+}
+
+func NewLexer(source string) *Lexer {
+	return &Lexer{
+		source:  source,
+		current: Position{line: 1, column: 1, offset: 0},
+	}
+}
+
+func (lexer *Lexer) isAtEnd() bool {
+	return lexer.current.offset >= len(lexer.source)
+}
+
+func (lexer *Lexer) advance() rune {
+	ch, size := utf8.DecodeRuneInString(lexer.source[lexer.current.offset:])
+	lexer.current.offset += size
+	if ch == '\n' {
+		lexer.current.line++
+		lexer.current.column = 1
+	} else {
+		lexer.current.column++
+	}
+
+	return ch
+}
+
+// advanceValid is advance for text taken as written, such as string and
+// comment bodies, reporting a byte that isn't valid UTF-8 rather than letting
+// it become U+FFFD.
+func (lexer *Lexer) advanceValid() (rune, *Diagnostic) {
+	start := lexer.current
+	ch := lexer.advance()
+	if ch == utf8.RuneError && lexer.current.offset-start.offset == 1 {
+		return ch, newDiagnostic(PhaseLex, "invalid-utf8", Span{start: start, end: lexer.current}, "Invalid UTF-8 encoding.")
+	}
+
+	return ch, nil
+}
+
+func (lexer *Lexer) peek() rune {
+	if lexer.isAtEnd() {
+		return 0
+	}
+	ch, _ := utf8.DecodeRuneInString(lexer.source[lexer.current.offset:])
+	return ch
+}
+
+func (lexer *Lexer) peekNext() rune {
+	if lexer.isAtEnd() {
+		return 0
+	}
+	_, size := utf8.DecodeRuneInString(lexer.source[lexer.current.offset:])
+	if lexer.current.offset+size >= len(lexer.source) {
+		return 0
+	}
+	ch, _ := utf8.DecodeRuneInString(lexer.source[lexer.current.offset+size:])
+	return ch
+}
+
+func (lexer *Lexer) match(expected rune) bool {
+	if lexer.peek() != expected || lexer.isAtEnd() {
+		return false
+	}
+
+	lexer.advance()
+	return true
+}
+
+func (lexer *Lexer) text() string {
+	return lexer.source[lexer.start.offset:lexer.current.offset]
+}
+
+func (lexer *Lexer) error(code string, msg string) *Diagnostic {
+	return newDiagnostic(PhaseLex, code, Span{start: lexer.start, end: lexer.current}, msg)
+}
+
+// scanTokens tokenizes the whole source. Scanning continues past errors so
+// that every problem in the input is reported; the returned tokens always end
+// with an EOF token.
+func (lexer *Lexer) scanTokens() ([]Token, []*Diagnostic) {
+	tokens := []Token{}
+	errs := []*Diagnostic{}
+	for !lexer.isAtEnd() {
+		token, err := lexer.scanToken()
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if token != (Token{}) {
+			tokens = append(tokens, token)
+		}
+	}
+
+	for i := len(lexer.interpolations) - 1; i >= 0; i-- {
+		// input ran out inside an interpolated expression
+		err := newDiagnostic(PhaseLex, "unterminated-string", Span{start: lexer.interpolations[i].quote, end: lexer.current}, "Unterminated string.")
+		err.line = lexer.current.line
+		errs = append(errs, err)
+	}
+
+	lexer.start = lexer.current
+	eof := Token{start: lexer.current, end: lexer.current}
+	eof.setToken(EOF, "")
+	tokens = append(tokens, eof)
+
+	return tokens, errs
+}
+
+// scanToken reads the next token. Whitespace and comments produce an empty
+// Token.
+func (lexer *Lexer) scanToken() (Token, *Diagnostic) {
+	lexer.start = lexer.current
+	token := Token{}
+	var diagnostic *Diagnostic
+	ch := lexer.advance()
+
+	switch ch {
+	case '(':
+		token.setToken(LEFT_PAREN, lexer.text())
+	case ')':
+		token.setToken(RIGHT_PAREN, lexer.text())
+	case '{':
+		if n := len(lexer.interpolations); n > 0 {
+			lexer.interpolations[n-1].braces++
+		}
+		token.setToken(LEFT_BRACE, lexer.text())
+	case '}':
+		n := len(lexer.interpolations)
+		if n > 0 && lexer.interpolations[n-1].braces == 0 {
+			// the end of an interpolated expression; the string goes on
+			quote := lexer.interpolations[n-1].quote
+			lexer.interpolations = lexer.interpolations[:n-1]
+			token, diagnostic = lexer.stringToken(quote, true)
+			break
+		}
+		if n > 0 {
+			lexer.interpolations[n-1].braces--
+		}
+		token.setToken(RIGHT_BRACE, lexer.text())
+	case ',':
+		token.setToken(COMMA, lexer.text())
+	case '.':
+		token.setToken(DOT, lexer.text())
+	case '*':
+		token.setToken(STAR, lexer.text())
+	case '+':
+		token.setToken(PLUS, lexer.text())
+	case '-':
+		token.setToken(MINUS, lexer.text())
+	case ';':
+		token.setToken(SEMICOLON, lexer.text())
+	case '=':
+		if lexer.match('=') {
+			token.setToken(EQUAL_EQUAL, lexer.text())
+		} else {
+			token.setToken(EQUAL, lexer.text())
+		}
+	case '!':
+		if lexer.match('=') {
+			token.setToken(BANG_EQUAL, lexer.text())
+		} else {
+			token.setToken(BANG, lexer.text())
+		}
+	case '<':
+		if lexer.match('=') {
+			token.setToken(LESS_EQUAL, lexer.text())
+		} else {
+			token.setToken(LESS, lexer.text())
+		}
+	case '>':
+		if lexer.match('=') {
+			token.setToken(GREATER_EQUAL, lexer.text())
+		} else {
+			token.setToken(GREATER, lexer.text())
+		}
+	case '/':
+		if lexer.match('/') {
+			for !lexer.isAtEnd() && lexer.peek() != '\n' {
+				if _, err := lexer.advanceValid(); err != nil && diagnostic == nil {
+					diagnostic = err
+				}
+			}
+			lexer.addComment()
+		} else if lexer.match('*') {
+			diagnostic = lexer.readBlockComment()
+			if diagnostic != nil && diagnostic.Code == "unterminated-comment" {
+				return token, diagnostic
+			}
+			lexer.addComment()
+		} else {
+			token.setToken(SLASH, lexer.text())
+		}
+	case ' ', '\t', '\r', '\n':
+		break
+	case '"':
+		token, diagnostic = lexer.stringToken(lexer.start, false)
+	default:
+		if ch == utf8.RuneError && lexer.current.offset-lexer.start.offset == 1 {
+			return token, lexer.error("invalid-utf8", "Invalid UTF-8 encoding.")
+		} else if isDigit(ch) {
+			literal, err := lexer.readNumber(ch)
+			token.setToken(NUMBER, lexer.text(), literal)
+			diagnostic = err
+		} else if lexer.isIdentifierStart(ch) {
+			str := lexer.readIdentifier()
+			if _, isKeyword := keywords[str]; isKeyword {
+				token.setToken(keywords[str], str)
+			} else {
+				token.setToken(IDENTIFIER, str)
+			}
+		} else {
+			return token, lexer.error("unexpected-character", fmt.Sprintf("Unexpected character: %c", ch))
+		}
+	}
+
+	if token != (Token{}) {
+		token.start = lexer.start
+		token.end = lexer.current
+	}
+	return token, diagnostic
+}
+
+// stringToken scans a string that opens at quote, or when resumed the segment
+// of one that follows an interpolated expression, up to its closing quote or
+// the next ${. A string that stops at a ${ is an INTERPOLATION token, and the
+// tokens of the embedded expression come next. The segments after it are
+// INTERPOLATION_MIDDLE tokens until the last, an INTERPOLATION_END.
+func (lexer *Lexer) stringToken(quote Position, resumed bool) (Token, *Diagnostic) {
+	token := Token{}
+	str, interpolated, err := lexer.readString()
+	if err != nil && err.Code == "unterminated-string" {
+		err.Span.start = quote
+	}
+	if err != nil && err.Code != "invalid-escape" && err.Code != "invalid-utf8" {
+		return token, err
+	}
+
+	// a string with a bad escape or byte still makes a token, so the parser
+	// doesn't report a second error for the missing expression
+	tokenType := STRING
+	switch {
+	case resumed && interpolated:
+		tokenType = INTERPOLATION_MIDDLE
+	case resumed:
+		tokenType = INTERPOLATION_END
+	case interpolated:
+		tokenType = INTERPOLATION
+	}
+	if interpolated {
+		lexer.interpolations = append(lexer.interpolations, openInterpolation{quote: quote})
+	}
+	token.setToken(tokenType, lexer.text(), str)
+	return token, err
+}
+
+// readString reads the rest of a string literal, or of one segment of it, which
+// may span lines, and returns its value with escape sequences decoded.
+// The bool reports whether the segment ended at a ${, with an interpolated
+// expression to follow, rather than at the closing quote. The first invalid
+// escape or invalid UTF-8 byte, if any, is returned alongside the value.
+func (lexer *Lexer) readString() (string, bool, *Diagnostic) {
+	var str strings.Builder
+	var contentErr *Diagnostic
+	for !lexer.isAtEnd() && lexer.peek() != '"' {
+		if lexer.peek() == '$' && lexer.peekNext() == '{' {
+			lexer.advance()
+			lexer.advance()
+			return str.String(), true, contentErr
+		}
+
+		escapeStart := lexer.current
+		ch, err := lexer.advanceValid()
+		if err != nil && contentErr == nil {
+			contentErr = err
+		}
+		if ch != '\\' {
+			str.WriteRune(ch)
+			continue
+		}
+
+		escaped, err := lexer.readEscape(escapeStart)
+		if err != nil && contentErr == nil {
+			contentErr = err
+		}
+		str.WriteString(escaped)
+	}
+
+	if lexer.isAtEnd() {
+		// reported on the line where the input ran out, like the reference
+		err := lexer.error("unterminated-string", "Unterminated string.")
+		err.line = lexer.current.line
+		return "", false, err
+	}
+
+	// closing quote
+	lexer.advance()
+	return str.String(), false, contentErr
+}
+
+// readEscape decodes the escape sequence after a backslash at start:
+// \n, \t, \\, \", \$ or \u{XXXX} with one to six hex digits.
+func (lexer *Lexer) readEscape(start Position) (string, *Diagnostic) {
+	if lexer.isAtEnd() {
+		// left for readString to report as unterminated
+		return "", nil
+	}
+
+	ch := lexer.advance()
+	switch ch {
+	case 'n':
+		return "\n", nil
+	case 't':
+		return "\t", nil
+	case '\\':
+		return "\\", nil
+	case '"':
+		return "\"", nil
+	case '$':
+		return "$", nil
+	case 'u':
+		return lexer.readUnicodeEscape(start)
+	default:
+		return "", newDiagnostic(PhaseLex, "invalid-escape", Span{start: start, end: lexer.current},
+			fmt.Sprintf("Invalid escape sequence '\\%c'.", ch))
+	}
+}
+
+func (lexer *Lexer) readUnicodeEscape(start Position) (string, *Diagnostic) {
+	invalid := func() (string, *Diagnostic) {
+		return "", newDiagnostic(PhaseLex, "invalid-escape", Span{start: start, end: lexer.current},
+			"Invalid unicode escape sequence, expected '\\u{' followed by 1 to 6 hex digits and '}'.")
+	}
+
+	if !lexer.match('{') {
+		return invalid()
+	}
+	digitsStart := lexer.current.offset
+	for isHexDigit(lexer.peek()) {
+		lexer.advance()
+	}
+	digits := lexer.source[digitsStart:lexer.current.offset]
+	if !lexer.match('}') || len(digits) == 0 || len(digits) > 6 {
+		return invalid()
+	}
+
+	code, _ := strconv.ParseUint(digits, 16, 32)
+	if !utf8.ValidRune(rune(code)) {
+		return "", newDiagnostic(PhaseLex, "invalid-escape", Span{start: start, end: lexer.current},
+			fmt.Sprintf("Invalid unicode code point U+%s.", strings.ToUpper(digits)))
+	}
+
+	return string(rune(code)), nil
+}
+
+// readBlockComment skips the rest of a /* ... */ comment whose opening has
+// already been consumed. Block comments nest, so each inner /* needs its own */.
+// An unterminated comment is reported at its opening; otherwise the first
+// invalid UTF-8 byte in it, if any, is returned.
+func (lexer *Lexer) readBlockComment() *Diagnostic {
+	depth := 1
+	var utf8Err *Diagnostic
+	for !lexer.isAtEnd() {
+		if lexer.peek() == '/' && lexer.peekNext() == '*' {
+			lexer.advance()
+			lexer.advance()
+			depth++
+		} else if lexer.peek() == '*' && lexer.peekNext() == '/' {
+			lexer.advance()
+			lexer.advance()
+			depth--
+			if depth == 0 {
+				return utf8Err
+			}
+		} else if _, err := lexer.advanceValid(); err != nil && utf8Err == nil {
+			utf8Err = err
+		}
+	}
+
+	opening := lexer.start
+	opening.column += 2
+	opening.offset += 2
+	return newDiagnostic(PhaseLex, "unterminated-comment", Span{start: lexer.start, end: opening}, "Unterminated block comment.")
+}
+
+func (lexer *Lexer) addComment() {
+	comment := Token{start: lexer.start, end: lexer.current}
+	comment.setToken(COMMENT, lexer.text())
+	lexer.comments = append(lexer.comments, comment)
+}
+
+// readNumber scans the rest of a number literal whose first digit is ch and
+// returns its canonical literal text. Decimal literals may have a fraction and
+// an exponent; 0x and 0b introduce hexadecimal and binary integers. Digits may
+// be grouped with single underscores between them, as in 1_000_000.
+func (lexer *Lexer) readNumber(ch rune) (string, *Diagnostic) {
+	if ch == '0' && (lexer.peek() == 'x' || lexer.peek() == 'X') {
+		lexer.advance()
+		return lexer.readRadixNumber(16, isHexDigit, "hexadecimal")
+	}
+	if ch == '0' && (lexer.peek() == 'b' || lexer.peek() == 'B') {
+		lexer.advance()
+		return lexer.readRadixNumber(2, isBinaryDigit, "binary")
+	}
+
+	var digits strings.Builder
+	digits.WriteRune(ch)
+	if err := lexer.readDigits(&digits, isDigit); err != nil {
+		return numberLiteral(0), err
+	}
+
+	if lexer.peek() == '.' && isDigit(lexer.peekNext()) {
+		digits.WriteRune(lexer.advance())
+		if err := lexer.readDigits(&digits, isDigit); err != nil {
+			return numberLiteral(0), err
+		}
+	}
+
+	if lexer.peek() == 'e' || lexer.peek() == 'E' {
+		digits.WriteRune(lexer.advance())
+		if lexer.peek() == '+' || lexer.peek() == '-' {
+			digits.WriteRune(lexer.advance())
+		}
+		if !isDigit(lexer.peek()) {
+			return numberLiteral(0), lexer.error("invalid-number", "Expect digits in exponent.")
+		}
+		if err := lexer.readDigits(&digits, isDigit); err != nil {
+			return numberLiteral(0), err
+		}
+	}
+
+	val, err := strconv.ParseFloat(digits.String(), 64)
+	if err != nil {
+		return numberLiteral(0), lexer.error("invalid-number", "Number literal is too large.")
+	}
+
+	return numberLiteral(val), nil
+}
+
+// readRadixNumber scans the digits of a hexadecimal or binary literal after
+// its prefix. Letters and digits that run on from the literal are reported
+// rather than scanned as a separate token.
+func (lexer *Lexer) readRadixNumber(base int, valid func(rune) bool, name string) (string, *Diagnostic) {
+	if !valid(lexer.peek()) {
+		lexer.skipAlphaNum()
+		return numberLiteral(0), lexer.error("invalid-number", fmt.Sprintf("Expect %s digits after '%s'.", name, lexer.text()[:2]))
+	}
+
+	var digits strings.Builder
+	if err := lexer.readDigits(&digits, valid); err != nil {
+		return numberLiteral(0), err
+	}
+	if lexer.isAlphaNum(lexer.peek()) {
+		ch := lexer.peek()
+		lexer.skipAlphaNum()
+		return numberLiteral(0), lexer.error("invalid-number", fmt.Sprintf("Invalid digit '%c' in %s literal.", ch, name))
+	}
+
+	val, err := strconv.ParseUint(digits.String(), base, 64)
+	if err != nil {
+		return numberLiteral(0), lexer.error("invalid-number", "Number literal is too large.")
+	}
+
+	return numberLiteral(float64(val)), nil
+}
+
+// readDigits appends a run of digits to digits, dropping the underscores that
+// separate them. An underscore must be followed by a digit.
+func (lexer *Lexer) readDigits(digits *strings.Builder, valid func(rune) bool) *Diagnostic {
+	for {
+		if lexer.peek() == '_' {
+			lexer.advance()
+			if !valid(lexer.peek()) {
+				lexer.skipAlphaNum()
+				return lexer.error("invalid-number", "Expect digit after '_' in number.")
+			}
+		} else if valid(lexer.peek()) {
+			digits.WriteRune(lexer.advance())
+		} else {
+			return nil
+		}
+	}
+}
+
+func (lexer *Lexer) skipAlphaNum() {
+	for lexer.isAlphaNum(lexer.peek()) {
+		lexer.advance()
+	}
+}
+
+// numberLiteral is the canonical literal text of a number token: the shortest
+// decimal that reads back as val, always with a fractional part, so 42 is
+// "42.0" and 0x10 is "16.0".
+func numberLiteral(val float64) string {
+	str := formatNumber(val)
+	if !strings.Contains(str, ".") {
+		str += ".0"
+	}
+
+	return str
+}
+
+func (lexer *Lexer) readIdentifier() string {
+	for lexer.isAlphaNum(lexer.peek()) {
+		lexer.advance()
+	}
+
+	return lexer.text()
+}
+
+func isDigit(ch rune) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isBinaryDigit(ch rune) bool {
+	return ch == '0' || ch == '1'
+}
+
+func isHexDigit(ch rune) bool {
+	return isDigit(ch) || (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')
+}
+
+func (lexer *Lexer) isIdentifierStart(ch rune) bool {
+	if lexer.unicodeIdentifiers && unicode.IsLetter(ch) {
+		return true
+	}
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch == '_'
+}
+
+func (lexer *Lexer) isAlphaNum(ch rune) bool {
+	if lexer.unicodeIdentifiers && unicode.IsDigit(ch) {
+		return true
+	}
+	return lexer.isIdentifierStart(ch) || isDigit(ch)
+}
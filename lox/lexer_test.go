@@ -0,0 +1,221 @@
+package lox
+
+import "testing"
+
+func TestInterpolationTokens(t *testing.T) {
+	tokens, errs := NewLexer(`"a ${ {b} } c ${"d${e}"}"`).scanTokens()
+	if len(errs) > 0 {
+		t.Fatalf("unexpected error %v", errs[0])
+	}
+
+	want := []string{INTERPOLATION, LEFT_BRACE, IDENTIFIER, RIGHT_BRACE, INTERPOLATION_MIDDLE,
+		INTERPOLATION, IDENTIFIER, INTERPOLATION_END, INTERPOLATION_END, EOF}
+	if len(tokens) != len(want) {
+		t.Fatalf("got %d tokens %v, want %d", len(tokens), tokens, len(want))
+	}
+	for i, token := range tokens {
+		if token.TokenType != want[i] {
+			t.Errorf("token %d is %s, want %s", i, token, want[i])
+		}
+	}
+}
+
+func TestUnterminatedInterpolation(t *testing.T) {
+	_, errs := NewLexer(`print "a${x`).scanTokens()
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors, want 1", len(errs))
+	}
+	if start := errs[0].Span.Start(); start.Column() != 7 {
+		t.Errorf("error starts at column %d, want the opening quote at 7", start.Column())
+	}
+}
+
+func TestInvalidUTF8(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		column int
+	}{
+		{"outside tokens", "print \xff;", 7},
+		{"in a string", "print \"é\xffb\";", 9},
+		{"in a line comment", "// é\xff\nprint 1;", 5},
+		{"in a block comment", "/* \xff */ print 1;", 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, errs := NewLexer(test.source).scanTokens()
+			if len(errs) != 1 {
+				t.Fatalf("got %d errors, want 1", len(errs))
+			}
+			if errs[0].Code != "invalid-utf8" {
+				t.Errorf("error code = %q, want invalid-utf8", errs[0].Code)
+			}
+			if column := errs[0].Span.Start().Column(); column != test.column {
+				t.Errorf("error at column %d, want %d", column, test.column)
+			}
+		})
+	}
+}
+
+func TestColumnsCountCharacters(t *testing.T) {
+	tokens, errs := NewLexer(`"hé€" x`).scanTokens()
+	if len(errs) > 0 {
+		t.Fatalf("unexpected error %v", errs[0])
+	}
+	if start := tokens[1].start; start.column != 7 || start.offset != 9 {
+		t.Errorf("x starts at column %d, offset %d; want column 7, offset 9", start.column, start.offset)
+	}
+}
+
+func TestStringEscapes(t *testing.T) {
+	tests := []struct {
+		source string
+		value  string
+	}{
+		{`"a\nb"`, "a\nb"},
+		{`"\t\\\""`, "\t\\\""},
+		{`"\u{41}\u{1F600}"`, "A\U0001F600"},
+		{"\"two\nlines\"", "two\nlines"},
+	}
+
+	for _, test := range tests {
+		tokens, errs := NewLexer(test.source).scanTokens()
+		if len(errs) > 0 {
+			t.Errorf("%s: unexpected error %v", test.source, errs[0])
+			continue
+		}
+		if tokens[0].TokenType != STRING || tokens[0].literal != test.value {
+			t.Errorf("%s: got %s, want STRING %q", test.source, tokens[0], test.value)
+		}
+	}
+}
+
+func TestInvalidEscapes(t *testing.T) {
+	tests := []struct {
+		source  string
+		message string
+	}{
+		{`"\q"`, "Invalid escape sequence '\\q'."},
+		{`"\u{}"`, "Invalid unicode escape sequence, expected '\\u{' followed by 1 to 6 hex digits and '}'."},
+		{`"\u{110000}"`, "Invalid unicode code point U+110000."},
+	}
+
+	for _, test := range tests {
+		tokens, errs := NewLexer(test.source).scanTokens()
+		if len(errs) != 1 || errs[0].Message != test.message {
+			t.Errorf("%s: got errors %v, want %q", test.source, errs, test.message)
+		}
+		// the string still makes a token so the parser doesn't pile on
+		if tokens[0].TokenType != STRING {
+			t.Errorf("%s: first token is %s, want a STRING", test.source, tokens[0])
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	lexer := NewLexer("// one\nprint /* two\n/* three */ */ 1;")
+	tokens, errs := lexer.scanTokens()
+	if len(errs) > 0 {
+		t.Fatalf("unexpected error %v", errs[0])
+	}
+
+	// comments are trivia, left out of the token stream
+	want := []string{PRINT, NUMBER, SEMICOLON, EOF}
+	if len(tokens) != len(want) {
+		t.Fatalf("got tokens %v, want %v", tokens, want)
+	}
+	if line := tokens[1].start.line; line != 3 {
+		t.Errorf("1 is on line %d, want 3", line)
+	}
+
+	comments := lexer.comments
+	wantComments := []string{"// one", "/* two\n/* three */ */"}
+	if len(comments) != len(wantComments) {
+		t.Fatalf("got %d comments, want %d", len(comments), len(wantComments))
+	}
+	for i, comment := range comments {
+		if comment.TokenType != COMMENT || comment.Lexeme() != wantComments[i] {
+			t.Errorf("comment %d = %s, want COMMENT %q", i, comment, wantComments[i])
+		}
+	}
+	if start := comments[1].Span().Start(); start.Line() != 2 || start.Column() != 7 {
+		t.Errorf("second comment starts at %d:%d, want 2:7", start.Line(), start.Column())
+	}
+}
+
+func TestUnterminatedComment(t *testing.T) {
+	_, errs := NewLexer("print 1;\n/* a /* b */\nc").scanTokens()
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors, want 1", len(errs))
+	}
+	err := errs[0]
+	if err.Message != "Unterminated block comment." || err.line != 2 || err.Span.Start().Column() != 1 {
+		t.Errorf("got %q on line %d column %d, want it at the opening on line 2",
+			err.Message, err.line, err.Span.Start().Column())
+	}
+}
+
+func TestNumberLiterals(t *testing.T) {
+	tests := []struct {
+		source  string
+		literal string
+	}{
+		{"42", "42.0"},
+		{"1234.1234", "1234.1234"},
+		{"0.5", "0.5"},
+		{"007", "7.0"},
+		{"1_000_000", "1000000.0"},
+		{"0xFF", "255.0"},
+		{"0x1_f", "31.0"},
+		{"0b1010", "10.0"},
+		{"1e9", "1000000000.0"},
+		{"2.5E-3", "0.0025"},
+		{"1e+2", "100.0"},
+	}
+
+	for _, test := range tests {
+		tokens, errs := NewLexer(test.source).scanTokens()
+		if len(errs) > 0 {
+			t.Errorf("%s: unexpected error %v", test.source, errs[0])
+			continue
+		}
+		if tokens[0].TokenType != NUMBER || tokens[0].literal != test.literal {
+			t.Errorf("%s: got %s, want NUMBER %s", test.source, tokens[0], test.literal)
+		}
+	}
+}
+
+func TestMalformedNumbers(t *testing.T) {
+	tests := []struct {
+		source  string
+		message string
+	}{
+		{"1__0", "Expect digit after '_' in number."},
+		{"1_", "Expect digit after '_' in number."},
+		{"1.5_", "Expect digit after '_' in number."},
+		{"0b102", "Invalid digit '2' in binary literal."},
+		{"0x", "Expect hexadecimal digits after '0x'."},
+		{"0xZZ", "Expect hexadecimal digits after '0x'."},
+		{"1e", "Expect digits in exponent."},
+		{"1e999", "Number literal is too large."},
+	}
+
+	for _, test := range tests {
+		tokens, errs := NewLexer(test.source).scanTokens()
+		if len(errs) != 1 || errs[0].Message != test.message {
+			t.Errorf("%s: got errors %v, want %q", test.source, errs, test.message)
+			continue
+		}
+		// the malformed number is a single token
+		if len(tokens) != 2 || tokens[0].lexeme != test.source {
+			t.Errorf("%s: got tokens %v, want one NUMBER and EOF", test.source, tokens)
+		}
+	}
+}
+
+func TestNumberArithmetic(t *testing.T) {
+	if got, want := runOutput(t, "print 0xFF + 0b11 + 1_000 + 1e2;"), "1358\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,526 @@
+package lox
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Expr interface {
+	String() string
+	Evaluate(interpreter *Interpreter, scope *Scope) (Value, error)
+	resolve(resolver *Resolver)
+	span() Span
+	toJSON() jsonNode
+}
+
+type Literal struct {
+	token Token
+	value string
+	t     string
+}
+
+func (l Literal) String() string {
+	return l.value
+}
+
+type Unary struct {
+	operator Token
+	right    Expr
+}
+
+func (u Unary) String() string {
+	return fmt.Sprintf("(%s %s)", u.operator.lexeme, u.right)
+}
+
+type Binary struct {
+	left     Expr
+	operator Token
+	right    Expr
+}
+
+func (b Binary) String() string {
+	return fmt.Sprintf("(%s %s %s)", b.operator.lexeme, b.left, b.right)
+}
+
+type Logical struct {
+	left     Expr
+	operator Token
+	right    Expr
+}
+
+func (l Logical) String() string {
+	return fmt.Sprintf("(%s %s %s)", l.operator.lexeme, l.left, l.right)
+}
+
+type Call struct {
+	callee    Expr
+	paren     Token
+	arguments []Expr
+}
+
+func (c Call) String() string {
+	str := fmt.Sprintf("(call %s", c.callee)
+	for _, arg := range c.arguments {
+		str += fmt.Sprintf(" %s", arg)
+	}
+	return str + ")"
+}
+
+type Get struct {
+	object Expr
+	name   Token
+}
+
+func (g Get) String() string {
+	return fmt.Sprintf("(. %s %s)", g.object, g.name.lexeme)
+}
+
+type Set struct {
+	object Expr
+	name   Token
+	value  Expr
+}
+
+func (s Set) String() string {
+	return fmt.Sprintf("(= (. %s %s) %s)", s.object, s.name.lexeme, s.value)
+}
+
+type This struct {
+	keyword Token
+	binding
+}
+
+func (t This) String() string {
+	return t.keyword.lexeme
+}
+
+type Super struct {
+	keyword Token
+	method  Token
+	binding
+}
+
+func (s Super) String() string {
+	return fmt.Sprintf("(super %s)", s.method.lexeme)
+}
+
+type Grouping struct {
+	leftParen  Token
+	expression Expr
+	rightParen Token
+}
+
+func (g Grouping) String() string {
+	return fmt.Sprintf("(group %s)", g.expression)
+}
+
+// Interpolation is a string with embedded expressions. Its parts are the
+// string segments, as Literals, and the expressions between them in order;
+// empty segments are left out.
+type Interpolation struct {
+	start Token
+	parts []Expr
+	end   Token
+}
+
+func (i Interpolation) String() string {
+	parts := make([]string, len(i.parts))
+	for n, part := range i.parts {
+		parts[n] = part.String()
+	}
+	return fmt.Sprintf("(interpolate %s)", strings.Join(parts, " "))
+}
+
+// binding is where the resolver found the declaration a name refers to. It
+// lives on the node so that a program's resolution goes away with its tree.
+type binding struct {
+	// depth is how many scopes out from the use the name is declared. It is
+	// only meaningful for a local; other names are looked up in the globals.
+	depth int
+	local bool
+}
+
+type Variable struct {
+	name Token
+	binding
+}
+
+func (v Variable) String() string {
+	return v.name.lexeme
+}
+
+type Assign struct {
+	name  Token
+	value Expr
+	binding
+}
+
+func (a Assign) String() string {
+	return fmt.Sprintf("(= %s %s)", a.name.lexeme, a.value)
+}
+
+func expression(parser *Parser) (Expr, error) {
+	return assignment(parser)
+}
+
+func assignment(parser *Parser) (Expr, error) {
+	expr, err := or(parser)
+	if err != nil {
+		return expr, err
+	}
+
+	if parser.match(EQUAL) {
+		equals := parser.previous()
+		value, err := assignment(parser)
+		if err != nil {
+			return &Assign{}, err
+		}
+
+		if variable, ok := expr.(*Variable); ok {
+			return &Assign{
+				name:  variable.name,
+				value: value,
+			}, nil
+		} else if get, ok := expr.(*Get); ok {
+			return &Set{
+				object: get.object,
+				name:   get.name,
+				value:  value,
+			}, nil
+		}
+
+		// report the bad target but keep parsing, the parser isn't confused
+		parser.errors = append(parser.errors, parser.error(equals, "invalid-assignment-target", "Invalid assignment target."))
+	}
+
+	return expr, nil
+}
+
+func or(parser *Parser) (Expr, error) {
+	expr, err := and(parser)
+	if err != nil {
+		return expr, err
+	}
+
+	for parser.match(OR) {
+		operator := parser.previous()
+		right, err := and(parser)
+		if err != nil {
+			return &Logical{}, err
+		}
+		expr = &Logical{
+			left:     expr,
+			operator: operator,
+			right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+func and(parser *Parser) (Expr, error) {
+	expr, err := equality(parser)
+	if err != nil {
+		return expr, err
+	}
+
+	for parser.match(AND) {
+		operator := parser.previous()
+		right, err := equality(parser)
+		if err != nil {
+			return &Logical{}, err
+		}
+		expr = &Logical{
+			left:     expr,
+			operator: operator,
+			right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+func equality(parser *Parser) (Expr, error) {
+	expr, err := comparison(parser)
+	if err != nil {
+		return expr, err
+	}
+
+	for parser.match(BANG_EQUAL, EQUAL_EQUAL) {
+		operator := parser.previous()
+		right, err := comparison(parser)
+		if err != nil {
+			return &Binary{}, err
+		}
+		expr = &Binary{
+			left:     expr,
+			operator: operator,
+			right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+func comparison(parser *Parser) (Expr, error) {
+	expr, err := term(parser)
+	if err != nil {
+		return expr, err
+	}
+
+	for parser.match(GREATER, GREATER_EQUAL, LESS, LESS_EQUAL) {
+		operator := parser.previous()
+		right, err := term(parser)
+		if err != nil {
+			return &Binary{}, err
+		}
+		expr = &Binary{
+			left:     expr,
+			operator: operator,
+			right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+func term(parser *Parser) (Expr, error) {
+	expr, err := factor(parser)
+	if err != nil {
+		return expr, err
+	}
+
+	for parser.match(MINUS, PLUS) {
+		operator := parser.previous()
+		right, err := factor(parser)
+		if err != nil {
+			return &Binary{}, err
+		}
+		expr = &Binary{
+			left:     expr,
+			operator: operator,
+			right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+func factor(parser *Parser) (Expr, error) {
+	expr, err := unary(parser)
+	if err != nil {
+		return expr, err
+	}
+
+	for parser.match(STAR, SLASH) {
+		operator := parser.previous()
+		right, err := unary(parser)
+		if err != nil {
+			return &Binary{}, err
+		}
+		expr = &Binary{
+			left:     expr,
+			operator: operator,
+			right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+func unary(parser *Parser) (Expr, error) {
+	if parser.match(BANG, MINUS) {
+		operator := parser.previous()
+		right, err := unary(parser)
+		if err != nil {
+			return &Binary{}, err
+		}
+		return &Unary{
+			operator: operator,
+			right:    right,
+		}, nil
+	}
+
+	return call(parser)
+}
+
+func call(parser *Parser) (Expr, error) {
+	expr, err := primary(parser)
+	if err != nil {
+		return expr, err
+	}
+
+	for {
+		if parser.match(LEFT_PAREN) {
+			expr, err = finishCall(parser, expr)
+			if err != nil {
+				return expr, err
+			}
+		} else if parser.match(DOT) {
+			name, err := consume(parser, IDENTIFIER, "Expect property name after '.'.")
+			if err != nil {
+				return &Get{}, err
+			}
+			expr = &Get{
+				object: expr,
+				name:   name,
+			}
+		} else {
+			break
+		}
+	}
+
+	return expr, nil
+}
+
+func finishCall(parser *Parser, callee Expr) (Expr, error) {
+	arguments := []Expr{}
+	if !parser.check(RIGHT_PAREN) {
+		for {
+			if len(arguments) >= maxArgs {
+				parser.errors = append(parser.errors, parser.error(parser.peek(), "too-many-arguments", fmt.Sprintf("Can't have more than %d arguments.", maxArgs)))
+			}
+
+			arg, err := expression(parser)
+			if err != nil {
+				return &Call{}, err
+			}
+			arguments = append(arguments, arg)
+
+			if !parser.match(COMMA) {
+				break
+			}
+		}
+	}
+
+	paren, err := consume(parser, RIGHT_PAREN, "Expect ')' after arguments.")
+	if err != nil {
+		return &Call{}, err
+	}
+
+	return &Call{
+		callee:    callee,
+		paren:     paren,
+		arguments: arguments,
+	}, nil
+}
+
+func primary(parser *Parser) (Expr, error) {
+	if parser.match(FALSE) {
+		return &Literal{
+			token: parser.previous(),
+			value: "false",
+			t:     "bool",
+		}, nil
+	} else if parser.match(TRUE) {
+		return &Literal{
+			token: parser.previous(),
+			value: "true",
+			t:     "bool",
+		}, nil
+	} else if parser.match(NIL) {
+		return &Literal{
+			token: parser.previous(),
+			value: "nil",
+			t:     "nil",
+		}, nil
+	} else if parser.match(STRING) {
+		return &Literal{
+			token: parser.previous(),
+			value: parser.previous().literal,
+			t:     "string",
+		}, nil
+	} else if parser.match(INTERPOLATION) {
+		return interpolation(parser)
+	} else if parser.match(NUMBER) {
+		return &Literal{
+			token: parser.previous(),
+			value: parser.previous().literal,
+			t:     "number",
+		}, nil
+	} else if parser.match(LEFT_PAREN) {
+		leftParen := parser.previous()
+		expr, err := expression(parser)
+		if err != nil {
+			return expr, err
+		}
+		rightParen, err := consume(parser, RIGHT_PAREN, "Expect ')' after expression.")
+		if err != nil {
+			return expr, err
+		}
+		return &Grouping{
+			leftParen:  leftParen,
+			expression: expr,
+			rightParen: rightParen,
+		}, nil
+	} else if parser.match(THIS) {
+		return &This{
+			keyword: parser.previous(),
+		}, nil
+	} else if parser.match(SUPER) {
+		keyword := parser.previous()
+		if _, err := consume(parser, DOT, "Expect '.' after 'super'."); err != nil {
+			return &Super{}, err
+		}
+		method, err := consume(parser, IDENTIFIER, "Expect superclass method name.")
+		if err != nil {
+			return &Super{}, err
+		}
+		return &Super{
+			keyword: keyword,
+			method:  method,
+		}, nil
+	} else if parser.match(IDENTIFIER) {
+		return &Variable{
+			name: parser.previous(),
+		}, nil
+	}
+
+	return &Grouping{}, parser.error(parser.peek(), "expect-expression", "Expect expression.")
+}
+
+// interpolation parses the rest of an interpolated string after its first
+// segment: an expression, then the next segment, until the INTERPOLATION_END.
+func interpolation(parser *Parser) (Expr, error) {
+	start := parser.previous()
+	parts := []Expr{}
+	segment := start
+	for {
+		if segment.literal != "" {
+			parts = append(parts, &Literal{
+				token: segment,
+				value: segment.literal,
+				t:     "string",
+			})
+		}
+		if segment.TokenType == INTERPOLATION_END {
+			break
+		}
+
+		expr, err := expression(parser)
+		if err != nil {
+			return expr, err
+		}
+		parts = append(parts, expr)
+
+		if !parser.match(INTERPOLATION_MIDDLE, INTERPOLATION_END) {
+			return expr, parser.error(parser.peek(), "expect-token", "Expect '}' after interpolated expression.")
+		}
+		segment = parser.previous()
+	}
+
+	return &Interpolation{
+		start: start,
+		parts: parts,
+		end:   segment,
+	}, nil
+}
+
+func consume(parser *Parser, tokenType string, msg string) (Token, error) {
+	if parser.check(tokenType) {
+		parser.advance()
+		return parser.previous(), nil
+	}
+
+	return parser.peek(), parser.error(parser.peek(), "expect-token", msg)
+}
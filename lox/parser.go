@@ -0,0 +1,510 @@
+package lox
+
+import (
+	"fmt"
+)
+
+// maxArgs is the most arguments a call, or parameters a function, may have.
+const maxArgs = 255
+
+type Parser struct {
+	tokens  []Token
+	current int
+	errors  []*Diagnostic
+}
+
+// parse parses a single expression and returns every syntax error found.
+func (parser *Parser) parse() (Expr, []*Diagnostic) {
+	expr, err := expression(parser)
+	if err != nil {
+		parser.errors = append(parser.errors, err.(*Diagnostic))
+	}
+
+	return expr, parser.errors
+}
+
+func (parser *Parser) match(tokenTypes ...string) bool {
+	for _, tokenType := range tokenTypes {
+		if parser.check(tokenType) {
+			// fmt.Printf("Match : %s and %s : at %d\n", tokenType, parser.peek().TokenType, parser.current)
+			parser.advance()
+			return true
+		}
+	}
+
+	return false
+}
+
+func (parser *Parser) check(tokenType string) bool {
+	if parser.isAtEnd() {
+		return false
+	}
+
+	return parser.peek().TokenType == tokenType
+}
+
+func (parser *Parser) peek() Token {
+	if parser.current >= len(parser.tokens) {
+		return Token{}
+	}
+	return parser.tokens[parser.current]
+}
+
+func (parser *Parser) advance() {
+	if !parser.isAtEnd() {
+		parser.current += 1
+	}
+}
+
+func (parser *Parser) previous() Token {
+	return parser.tokens[parser.current-1]
+}
+
+func (parser *Parser) isAtEnd() bool {
+	return parser.current >= len(parser.tokens) || parser.peek().TokenType == EOF
+}
+
+// parseStatements parses a whole program. After a syntax error the parser
+// skips to the next statement boundary and carries on, so every error in the
+// program is returned.
+func (parser *Parser) parseStatements() ([]Stmt, []*Diagnostic) {
+	stmts := []Stmt{}
+	for !parser.isAtEnd() {
+		stmt, err := declaration(parser)
+		if err != nil {
+			parser.recordError(err)
+			continue
+		}
+		stmts = append(stmts, stmt)
+	}
+
+	return stmts, parser.errors
+}
+
+// recordError keeps err and skips to the next statement boundary. Blocks
+// recover this way too, so an error inside one doesn't unwind out of it and
+// leave its closing brace to be misread at top level.
+func (parser *Parser) recordError(err error) {
+	parser.errors = append(parser.errors, err.(*Diagnostic))
+	parser.synchronize()
+}
+
+func (parser *Parser) error(token Token, code string, msg string) *Diagnostic {
+	return newTokenDiagnostic(PhaseParse, code, token, msg)
+}
+
+// synchronize discards tokens until the start of the next statement.
+func (parser *Parser) synchronize() {
+	parser.advance()
+
+	for !parser.isAtEnd() {
+		if parser.previous().TokenType == SEMICOLON {
+			return
+		}
+
+		switch parser.peek().TokenType {
+		case CLASS, FUN, VAR, FOR, IF, WHILE, PRINT, RETURN:
+			return
+		}
+
+		parser.advance()
+	}
+}
+
+type Stmt interface {
+	Execute(interpreter *Interpreter, scope *Scope) error
+	resolve(resolver *Resolver)
+}
+
+type PrintStmt struct {
+	expression Expr
+}
+
+type ExpressionStmt struct {
+	expression Expr
+}
+
+type VarStmt struct {
+	name        Token
+	initializer Expr
+}
+
+type BlockStmt struct {
+	statements []Stmt
+}
+
+type FunctionStmt struct {
+	name   Token
+	params []Token
+	body   []Stmt
+}
+
+type ClassStmt struct {
+	name       Token
+	superclass *Variable
+	methods    []*FunctionStmt
+}
+
+type ReturnStmt struct {
+	keyword Token
+	value   Expr
+}
+
+type IfStmt struct {
+	condition  Expr
+	thenBranch Stmt
+	elseBranch Stmt
+}
+
+type WhileStmt struct {
+	condition Expr
+	body      Stmt
+}
+
+func declaration(parser *Parser) (Stmt, error) {
+	if parser.match(CLASS) {
+		return classDeclaration(parser)
+	} else if parser.match(FUN) {
+		return function(parser, "function")
+	} else if parser.match(VAR) {
+		return varDeclaration(parser)
+	}
+
+	return statement(parser)
+}
+
+func classDeclaration(parser *Parser) (Stmt, error) {
+	name, err := consume(parser, IDENTIFIER, "Expect class name.")
+	if err != nil {
+		return nil, err
+	}
+
+	var superclass *Variable
+	if parser.match(LESS) {
+		superName, err := consume(parser, IDENTIFIER, "Expect superclass name.")
+		if err != nil {
+			return nil, err
+		}
+		superclass = &Variable{
+			name: superName,
+		}
+	}
+
+	if _, err := consume(parser, LEFT_BRACE, "Expect '{' before class body."); err != nil {
+		return nil, err
+	}
+	methods := []*FunctionStmt{}
+	for !parser.check(RIGHT_BRACE) && !parser.isAtEnd() {
+		method, err := function(parser, "method")
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, method)
+	}
+	if _, err := consume(parser, RIGHT_BRACE, "Expect '}' after class body."); err != nil {
+		return nil, err
+	}
+
+	return &ClassStmt{
+		name:       name,
+		superclass: superclass,
+		methods:    methods,
+	}, nil
+}
+
+// function parses the name, parameters and body of a function declaration;
+// kind is only used in error messages.
+func function(parser *Parser, kind string) (*FunctionStmt, error) {
+	name, err := consume(parser, IDENTIFIER, fmt.Sprintf("Expect %s name.", kind))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := consume(parser, LEFT_PAREN, fmt.Sprintf("Expect '(' after %s name.", kind)); err != nil {
+		return nil, err
+	}
+	params := []Token{}
+	if !parser.check(RIGHT_PAREN) {
+		for {
+			if len(params) >= maxArgs {
+				parser.errors = append(parser.errors, parser.error(parser.peek(), "too-many-parameters", fmt.Sprintf("Can't have more than %d parameters.", maxArgs)))
+			}
+
+			param, err := consume(parser, IDENTIFIER, "Expect parameter name.")
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, param)
+
+			if !parser.match(COMMA) {
+				break
+			}
+		}
+	}
+	if _, err := consume(parser, RIGHT_PAREN, "Expect ')' after parameters."); err != nil {
+		return nil, err
+	}
+
+	if _, err := consume(parser, LEFT_BRACE, fmt.Sprintf("Expect '{' before %s body.", kind)); err != nil {
+		return nil, err
+	}
+	body, err := blockStatements(parser)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FunctionStmt{
+		name:   name,
+		params: params,
+		body:   body,
+	}, nil
+}
+
+func varDeclaration(parser *Parser) (Stmt, error) {
+	name, err := consume(parser, IDENTIFIER, "Expect variable name.")
+	if err != nil {
+		return nil, err
+	}
+
+	var initializer Expr
+	if parser.match(EQUAL) {
+		expr, err := expression(parser)
+		if err != nil {
+			return nil, err
+		}
+		initializer = expr
+	}
+
+	if _, err := consume(parser, SEMICOLON, "Expect ';' after variable declaration."); err != nil {
+		return nil, err
+	}
+	return &VarStmt{
+		name:        name,
+		initializer: initializer,
+	}, nil
+}
+
+func statement(parser *Parser) (Stmt, error) {
+	if parser.match(FOR) {
+		return forStatement(parser)
+	} else if parser.match(IF) {
+		return ifStatement(parser)
+	} else if parser.match(PRINT) {
+		return printStatement(parser)
+	} else if parser.match(RETURN) {
+		return returnStatement(parser)
+	} else if parser.match(WHILE) {
+		return whileStatement(parser)
+	} else if parser.match(LEFT_BRACE) {
+		return block(parser)
+	}
+
+	return expressionStatement(parser)
+}
+
+func ifStatement(parser *Parser) (Stmt, error) {
+	if _, err := consume(parser, LEFT_PAREN, "Expect '(' after 'if'."); err != nil {
+		return nil, err
+	}
+	condition, err := expression(parser)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := consume(parser, RIGHT_PAREN, "Expect ')' after if condition."); err != nil {
+		return nil, err
+	}
+
+	thenBranch, err := statement(parser)
+	if err != nil {
+		return nil, err
+	}
+
+	var elseBranch Stmt
+	if parser.match(ELSE) {
+		elseBranch, err = statement(parser)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &IfStmt{
+		condition:  condition,
+		thenBranch: thenBranch,
+		elseBranch: elseBranch,
+	}, nil
+}
+
+func whileStatement(parser *Parser) (Stmt, error) {
+	if _, err := consume(parser, LEFT_PAREN, "Expect '(' after 'while'."); err != nil {
+		return nil, err
+	}
+	condition, err := expression(parser)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := consume(parser, RIGHT_PAREN, "Expect ')' after condition."); err != nil {
+		return nil, err
+	}
+
+	body, err := statement(parser)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WhileStmt{
+		condition: condition,
+		body:      body,
+	}, nil
+}
+
+// forStatement desugars a for loop into a while loop wrapped in a block that
+// holds the initializer:
+//
+//	{ initializer; while (condition) { body; increment; } }
+func forStatement(parser *Parser) (Stmt, error) {
+	if _, err := consume(parser, LEFT_PAREN, "Expect '(' after 'for'."); err != nil {
+		return nil, err
+	}
+
+	var initializer Stmt
+	var err error
+	if parser.match(SEMICOLON) {
+		initializer = nil
+	} else if parser.match(VAR) {
+		initializer, err = varDeclaration(parser)
+	} else {
+		initializer, err = expressionStatement(parser)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var condition Expr
+	if !parser.check(SEMICOLON) {
+		condition, err = expression(parser)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if _, err := consume(parser, SEMICOLON, "Expect ';' after loop condition."); err != nil {
+		return nil, err
+	}
+
+	var increment Expr
+	if !parser.check(RIGHT_PAREN) {
+		increment, err = expression(parser)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if _, err := consume(parser, RIGHT_PAREN, "Expect ')' after for clauses."); err != nil {
+		return nil, err
+	}
+
+	body, err := statement(parser)
+	if err != nil {
+		return nil, err
+	}
+
+	if increment != nil {
+		body = &BlockStmt{
+			statements: []Stmt{body, &ExpressionStmt{expression: increment}},
+		}
+	}
+	if condition == nil {
+		condition = &Literal{
+			value: "true",
+			t:     "bool",
+		}
+	}
+	body = &WhileStmt{
+		condition: condition,
+		body:      body,
+	}
+	if initializer != nil {
+		body = &BlockStmt{
+			statements: []Stmt{initializer, body},
+		}
+	}
+
+	return body, nil
+}
+
+func printStatement(parser *Parser) (Stmt, error) {
+	expr, err := expression(parser)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := consume(parser, SEMICOLON, "Expect ';' after value."); err != nil {
+		return nil, err
+	}
+	return &PrintStmt{
+		expression: expr,
+	}, nil
+}
+
+func returnStatement(parser *Parser) (Stmt, error) {
+	keyword := parser.previous()
+
+	var value Expr
+	if !parser.check(SEMICOLON) {
+		expr, err := expression(parser)
+		if err != nil {
+			return nil, err
+		}
+		value = expr
+	}
+
+	if _, err := consume(parser, SEMICOLON, "Expect ';' after return value."); err != nil {
+		return nil, err
+	}
+	return &ReturnStmt{
+		keyword: keyword,
+		value:   value,
+	}, nil
+}
+
+func block(parser *Parser) (Stmt, error) {
+	stmts, err := blockStatements(parser)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlockStmt{
+		statements: stmts,
+	}, nil
+}
+
+// blockStatements parses the declarations of a block up to and including the
+// closing brace.
+func blockStatements(parser *Parser) ([]Stmt, error) {
+	stmts := []Stmt{}
+	for !parser.check(RIGHT_BRACE) && !parser.isAtEnd() {
+		stmt, err := declaration(parser)
+		if err != nil {
+			parser.recordError(err)
+			continue
+		}
+		stmts = append(stmts, stmt)
+	}
+
+	if _, err := consume(parser, RIGHT_BRACE, "Expect '}' after block."); err != nil {
+		return nil, err
+	}
+	return stmts, nil
+}
+
+func expressionStatement(parser *Parser) (Stmt, error) {
+	expr, err := expression(parser)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := consume(parser, SEMICOLON, "Expect ';' after expression."); err != nil {
+		return nil, err
+	}
+	return &ExpressionStmt{
+		expression: expr,
+	}, nil
+}
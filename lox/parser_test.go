@@ -0,0 +1,93 @@
+package lox
+
+import "testing"
+
+// programDiagnostics scans and parses source as a program and returns every
+// error, rendered in the text format.
+func programDiagnostics(source string) []string {
+	tokens, errs := NewLexer(source).scanTokens()
+	parser := &Parser{tokens: tokens}
+	_, parseErrs := parser.parseStatements()
+
+	messages := []string{}
+	for _, err := range append(errs, parseErrs...) {
+		messages = append(messages, err.Error())
+	}
+	return messages
+}
+
+func TestParseRecovery(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		messages []string
+	}{
+		{
+			name:     "top level",
+			source:   "print 1 +;\nvar = 2;\nprint 3;",
+			messages: []string{"Expect expression.", "Expect variable name."},
+		},
+		{
+			name:     "inside a block",
+			source:   "{\n var x = ;\n print 1;\n}",
+			messages: []string{"Expect expression."},
+		},
+		{
+			name:     "nested blocks and function bodies",
+			source:   "fun f() {\n  var = 1;\n  if (true) { print ; }\n}\nprint 2 +;",
+			messages: []string{"Expect variable name.", "Expect expression.", "Expect expression."},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tokens, lexErrs := NewLexer(test.source).scanTokens()
+			if len(lexErrs) > 0 {
+				t.Fatalf("unexpected lex error %v", lexErrs[0])
+			}
+			parser := &Parser{tokens: tokens}
+			_, errs := parser.parseStatements()
+			if len(errs) != len(test.messages) {
+				t.Fatalf("got %d diagnostics %v, want %d", len(errs), errs, len(test.messages))
+			}
+			for i, err := range errs {
+				if err.Message != test.messages[i] {
+					t.Errorf("diagnostic %d = %q, want %q", i, err.Message, test.messages[i])
+				}
+			}
+		})
+	}
+}
+
+func TestInterpolationErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		errors []string
+	}{
+		{`print "a${1 +}b";`, []string{"[line 1] Error at '}': Expect expression."}},
+		{`print "${}";`, []string{"[line 1] Error at '}': Expect expression."}},
+		{`print "a${b c}";`, []string{"[line 1] Error at 'c': Expect '}' after interpolated expression."}},
+		{"print \"a${x", []string{
+			"[line 1] Error: Unterminated string.",
+			"[line 1] Error at end: Expect '}' after interpolated expression.",
+		}},
+		{"print \"a${\"b${x}\nc", []string{
+			"[line 2] Error: Unterminated string.",
+			"[line 2] Error: Unterminated string.",
+			"[line 2] Error at end: Expect '}' after interpolated expression.",
+		}},
+	}
+
+	for _, test := range tests {
+		errs := programDiagnostics(test.source)
+		if len(errs) != len(test.errors) {
+			t.Errorf("%q: got errors %q, want %q", test.source, errs, test.errors)
+			continue
+		}
+		for i, err := range errs {
+			if err != test.errors[i] {
+				t.Errorf("%q: error %d = %q, want %q", test.source, i, err, test.errors[i])
+			}
+		}
+	}
+}
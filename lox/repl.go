@@ -0,0 +1,89 @@
+package lox
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// REPL runs an interactive session reading source from in until it is
+// exhausted. Input is collected across lines until its braces balance, the
+// value of a bare expression is printed, and errors are reported without
+// ending the session. Definitions persist in the interpreter's globals.
+func (interpreter *Interpreter) REPL(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	source := ""
+
+	fmt.Fprint(interpreter.stdout, "> ")
+	for scanner.Scan() {
+		source += scanner.Text() + "\n"
+		if braceDepth(source) > 0 {
+			fmt.Fprint(interpreter.stdout, "... ")
+			continue
+		}
+
+		interpreter.replInput(source)
+		source = ""
+		fmt.Fprint(interpreter.stdout, "> ")
+	}
+
+	return scanner.Err()
+}
+
+// replInput runs one complete chunk of REPL input. Errors have already been
+// reported on stderr, so they are dropped here to keep the session going.
+func (interpreter *Interpreter) replInput(source string) {
+	interpreter.source = source
+	tokens, errs := interpreter.scan(source)
+	if len(errs) > 0 {
+		interpreter.syntaxError(errs...)
+		return
+	}
+
+	if expr := bareExpression(tokens); expr != nil {
+		val, err := interpreter.evaluate(expr)
+		if err == nil {
+			fmt.Fprintln(interpreter.stdout, Stringify(val))
+		}
+		return
+	}
+
+	interpreter.run(tokens, nil)
+}
+
+// bareExpression parses tokens as a single expression with an optional
+// trailing semicolon, returning nil if they are anything else.
+func bareExpression(tokens []Token) Expr {
+	parser := &Parser{
+		tokens:  tokens,
+		current: 0,
+	}
+	expr, errs := parser.parse()
+	if len(errs) > 0 {
+		return nil
+	}
+
+	parser.match(SEMICOLON)
+	if !parser.isAtEnd() {
+		return nil
+	}
+
+	return expr
+}
+
+// braceDepth counts how many braces in source are still open.
+func braceDepth(source string) int {
+	tokens, _ := NewLexer(source).scanTokens()
+
+	depth := 0
+	for _, token := range tokens {
+		switch token.TokenType {
+		case LEFT_BRACE:
+			depth++
+		case RIGHT_BRACE:
+			depth--
+		}
+	}
+
+	return depth
+}
@@ -0,0 +1,79 @@
+package lox
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// runREPL feeds input to a fresh interpreter's REPL and returns what it wrote
+// to stdout and stderr.
+func runREPL(t *testing.T, input string) (string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	interpreter := New(Options{Stdout: &stdout, Stderr: &stderr})
+	if err := interpreter.REPL(strings.NewReader(input)); err != nil {
+		t.Fatalf("REPL returned %v", err)
+	}
+
+	return stdout.String(), stderr.String()
+}
+
+func TestREPL(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		stdout string
+		stderr string
+	}{
+		{
+			name:   "bare expressions are printed",
+			input:  "1 + 2\n\"a\" + \"b\";\n",
+			stdout: "> 3\n> ab\n> ",
+		},
+		{
+			name:   "statements print only their own output",
+			input:  "print 4;\nvar x = 1;\n",
+			stdout: "> 4\n> > ",
+		},
+		{
+			name:   "globals persist across inputs",
+			input:  "var x = 10;\nfun double(n) { return n * 2; }\ndouble(x)\n",
+			stdout: "> > > 20\n> ",
+		},
+		{
+			name:   "input continues until braces balance",
+			input:  "if (true) {\n  print \"in\";\n}\n",
+			stdout: "> ... ... in\n> ",
+		},
+		{
+			name:   "a multi-line function can be called later",
+			input:  "fun f() {\n  return 7;\n}\nf()\n",
+			stdout: "> ... ... > 7\n> ",
+		},
+		{
+			name:   "the session recovers after a runtime error",
+			input:  "-\"a\"\nprint nope;\nprint 5;\n",
+			stdout: "> > > 5\n> ",
+			stderr: "Operand must be a number.\n[line 1]\nUndefined variable 'nope'.\n[line 1]\n",
+		},
+		{
+			name:   "the session recovers after a syntax error",
+			input:  "print ;\nprint 6;\n",
+			stdout: "> > 6\n> ",
+			stderr: "[line 1] Error at ';': Expect expression.\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdout, stderr := runREPL(t, test.input)
+			if stdout != test.stdout {
+				t.Errorf("stdout = %q, want %q", stdout, test.stdout)
+			}
+			if stderr != test.stderr {
+				t.Errorf("stderr = %q, want %q", stderr, test.stderr)
+			}
+		})
+	}
+}
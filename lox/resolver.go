@@ -0,0 +1,284 @@
+package lox
+
+type functionType int
+
+const (
+	noFunction functionType = iota
+	functionFunction
+	initializerFunction
+	methodFunction
+)
+
+type classType int
+
+const (
+	noClass classType = iota
+	classClass
+	subclassClass
+)
+
+// Resolver is a static pass over a parsed program. For every local variable
+// reference it records on the node how many scopes out the variable is
+// declared, and it reports semantic errors that can be found before running.
+type Resolver struct {
+	scopes          []map[string]bool
+	currentFunction functionType
+	currentClass    classType
+	errors          []*Diagnostic
+}
+
+func NewResolver() *Resolver {
+	return &Resolver{}
+}
+
+func (resolver *Resolver) resolveStatements(stmts []Stmt) []*Diagnostic {
+	for _, stmt := range stmts {
+		stmt.resolve(resolver)
+	}
+
+	return resolver.errors
+}
+
+func (resolver *Resolver) resolveExpression(expr Expr) []*Diagnostic {
+	expr.resolve(resolver)
+
+	return resolver.errors
+}
+
+func (resolver *Resolver) error(token Token, code string, msg string) {
+	resolver.errors = append(resolver.errors, newTokenDiagnostic(PhaseResolve, code, token, msg))
+}
+
+func (resolver *Resolver) beginScope() {
+	resolver.scopes = append(resolver.scopes, make(map[string]bool))
+}
+
+func (resolver *Resolver) endScope() {
+	resolver.scopes = resolver.scopes[:len(resolver.scopes)-1]
+}
+
+// declare adds name to the innermost scope, marked as not ready for use until
+// define is called.
+func (resolver *Resolver) declare(name Token) {
+	if len(resolver.scopes) == 0 {
+		return
+	}
+
+	scope := resolver.scopes[len(resolver.scopes)-1]
+	if _, ok := scope[name.lexeme]; ok {
+		resolver.error(name, "redeclared-variable", "Already a variable with this name in this scope.")
+	}
+	scope[name.lexeme] = false
+}
+
+func (resolver *Resolver) define(name Token) {
+	if len(resolver.scopes) == 0 {
+		return
+	}
+
+	resolver.scopes[len(resolver.scopes)-1][name.lexeme] = true
+}
+
+// resolveLocal records the depth of the innermost scope declaring name. Names
+// not found in any scope are left unresolved and treated as globals.
+func (resolver *Resolver) resolveLocal(binding *binding, name Token) {
+	for i := len(resolver.scopes) - 1; i >= 0; i-- {
+		if _, ok := resolver.scopes[i][name.lexeme]; ok {
+			binding.depth = len(resolver.scopes) - 1 - i
+			binding.local = true
+			return
+		}
+	}
+}
+
+func (resolver *Resolver) resolveFunction(function *FunctionStmt, kind functionType) {
+	enclosingFunction := resolver.currentFunction
+	resolver.currentFunction = kind
+	defer func() {
+		resolver.currentFunction = enclosingFunction
+	}()
+
+	resolver.beginScope()
+	for _, param := range function.params {
+		resolver.declare(param)
+		resolver.define(param)
+	}
+	for _, stmt := range function.body {
+		stmt.resolve(resolver)
+	}
+	resolver.endScope()
+}
+
+func (b *BlockStmt) resolve(resolver *Resolver) {
+	resolver.beginScope()
+	for _, stmt := range b.statements {
+		stmt.resolve(resolver)
+	}
+	resolver.endScope()
+}
+
+func (c *ClassStmt) resolve(resolver *Resolver) {
+	enclosingClass := resolver.currentClass
+	resolver.currentClass = classClass
+	defer func() {
+		resolver.currentClass = enclosingClass
+	}()
+
+	resolver.declare(c.name)
+	resolver.define(c.name)
+
+	if c.superclass != nil {
+		if c.superclass.name.lexeme == c.name.lexeme {
+			resolver.error(c.superclass.name, "self-inheritance", "A class can't inherit from itself.")
+		}
+
+		resolver.currentClass = subclassClass
+		c.superclass.resolve(resolver)
+
+		resolver.beginScope()
+		resolver.scopes[len(resolver.scopes)-1]["super"] = true
+	}
+
+	resolver.beginScope()
+	resolver.scopes[len(resolver.scopes)-1]["this"] = true
+
+	for _, method := range c.methods {
+		kind := methodFunction
+		if method.name.lexeme == "init" {
+			kind = initializerFunction
+		}
+		resolver.resolveFunction(method, kind)
+	}
+
+	resolver.endScope()
+
+	if c.superclass != nil {
+		resolver.endScope()
+	}
+}
+
+func (e *ExpressionStmt) resolve(resolver *Resolver) {
+	e.expression.resolve(resolver)
+}
+
+func (f *FunctionStmt) resolve(resolver *Resolver) {
+	// define the name before the body so the function can call itself
+	resolver.declare(f.name)
+	resolver.define(f.name)
+
+	resolver.resolveFunction(f, functionFunction)
+}
+
+func (i *IfStmt) resolve(resolver *Resolver) {
+	i.condition.resolve(resolver)
+	i.thenBranch.resolve(resolver)
+	if i.elseBranch != nil {
+		i.elseBranch.resolve(resolver)
+	}
+}
+
+func (p *PrintStmt) resolve(resolver *Resolver) {
+	p.expression.resolve(resolver)
+}
+
+func (r *ReturnStmt) resolve(resolver *Resolver) {
+	if resolver.currentFunction == noFunction {
+		resolver.error(r.keyword, "top-level-return", "Can't return from top-level code.")
+	}
+
+	if r.value != nil {
+		if resolver.currentFunction == initializerFunction {
+			resolver.error(r.keyword, "initializer-return", "Can't return a value from an initializer.")
+		}
+		r.value.resolve(resolver)
+	}
+}
+
+func (v *VarStmt) resolve(resolver *Resolver) {
+	resolver.declare(v.name)
+	if v.initializer != nil {
+		v.initializer.resolve(resolver)
+	}
+	resolver.define(v.name)
+}
+
+func (w *WhileStmt) resolve(resolver *Resolver) {
+	w.condition.resolve(resolver)
+	w.body.resolve(resolver)
+}
+
+func (a *Assign) resolve(resolver *Resolver) {
+	a.value.resolve(resolver)
+	resolver.resolveLocal(&a.binding, a.name)
+}
+
+func (b *Binary) resolve(resolver *Resolver) {
+	b.left.resolve(resolver)
+	b.right.resolve(resolver)
+}
+
+func (c *Call) resolve(resolver *Resolver) {
+	c.callee.resolve(resolver)
+	for _, argument := range c.arguments {
+		argument.resolve(resolver)
+	}
+}
+
+func (g *Get) resolve(resolver *Resolver) {
+	g.object.resolve(resolver)
+}
+
+func (i *Interpolation) resolve(resolver *Resolver) {
+	for _, part := range i.parts {
+		part.resolve(resolver)
+	}
+}
+
+func (g *Grouping) resolve(resolver *Resolver) {
+	g.expression.resolve(resolver)
+}
+
+func (l *Literal) resolve(resolver *Resolver) {}
+
+func (l *Logical) resolve(resolver *Resolver) {
+	l.left.resolve(resolver)
+	l.right.resolve(resolver)
+}
+
+func (s *Set) resolve(resolver *Resolver) {
+	s.value.resolve(resolver)
+	s.object.resolve(resolver)
+}
+
+func (s *Super) resolve(resolver *Resolver) {
+	if resolver.currentClass == noClass {
+		resolver.error(s.keyword, "super-outside-class", "Can't use 'super' outside of a class.")
+	} else if resolver.currentClass != subclassClass {
+		resolver.error(s.keyword, "super-without-superclass", "Can't use 'super' in a class with no superclass.")
+	}
+
+	resolver.resolveLocal(&s.binding, s.keyword)
+}
+
+func (t *This) resolve(resolver *Resolver) {
+	if resolver.currentClass == noClass {
+		resolver.error(t.keyword, "this-outside-class", "Can't use 'this' outside of a class.")
+		return
+	}
+
+	resolver.resolveLocal(&t.binding, t.keyword)
+}
+
+func (u *Unary) resolve(resolver *Resolver) {
+	u.right.resolve(resolver)
+}
+
+func (v *Variable) resolve(resolver *Resolver) {
+	if len(resolver.scopes) > 0 {
+		if defined, ok := resolver.scopes[len(resolver.scopes)-1][v.name.lexeme]; ok && !defined {
+			resolver.error(v.name, "self-initializer", "Can't read local variable in its own initializer.")
+		}
+	}
+
+	resolver.resolveLocal(&v.binding, v.name)
+}
@@ -0,0 +1,93 @@
+package lox
+
+import (
+	"bytes"
+	"testing"
+)
+
+// resolveDiagnostics parses source, which must be free of syntax errors, and
+// returns the resolver's errors.
+func resolveDiagnostics(t *testing.T, source string) []*Diagnostic {
+	t.Helper()
+	tokens, errs := NewLexer(source).scanTokens()
+	parser := &Parser{tokens: tokens}
+	stmts, parseErrs := parser.parseStatements()
+	if errs = append(errs, parseErrs...); len(errs) > 0 {
+		t.Fatalf("unexpected syntax error %v", errs[0])
+	}
+
+	return NewResolver().resolveStatements(stmts)
+}
+
+func TestResolverErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		code   string
+	}{
+		{"{ var a = 1; var a = 2; }", "redeclared-variable"},
+		{"{ var a = a; }", "self-initializer"},
+		{"return 1;", "top-level-return"},
+		{"class A { init() { return 1; } }", "initializer-return"},
+		{"print this;", "this-outside-class"},
+		{"fun f() { super.g(); }", "super-outside-class"},
+		{"class A { f() { super.g(); } }", "super-without-superclass"},
+		{"class A < A {}", "self-inheritance"},
+	}
+
+	for _, test := range tests {
+		errs := resolveDiagnostics(t, test.source)
+		if len(errs) != 1 || errs[0].Code != test.code {
+			t.Errorf("%q: got %v, want one %s error", test.source, errs, test.code)
+		}
+	}
+}
+
+func TestResolverAllowed(t *testing.T) {
+	sources := []string{
+		"var a = 1; var a = 2;",
+		"{ var a = 1; { var a = 2; } }",
+		"class A { init() { return; } }",
+		"class A { f() { return this; } }",
+	}
+
+	for _, source := range sources {
+		if errs := resolveDiagnostics(t, source); len(errs) > 0 {
+			t.Errorf("%q: unexpected error %v", source, errs[0])
+		}
+	}
+}
+
+func TestResolvedScopes(t *testing.T) {
+	// the closure must keep seeing the a it was defined with, not the one
+	// declared after it in the same block
+	source := `
+var a = "global";
+{
+  fun show() { print a; }
+  show();
+  var a = "block";
+  show();
+}`
+	if got, want := runOutput(t, source), "global\nglobal\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestResolutionAcrossRuns(t *testing.T) {
+	var stdout bytes.Buffer
+	interpreter := New(Options{Stdout: &stdout, Stderr: &bytes.Buffer{}})
+	sources := []string{
+		"fun counter() { var n = 0; fun inc() { n = n + 1; return n; } return inc; }",
+		"var c = counter();",
+		"c(); print c();",
+	}
+	for _, source := range sources {
+		if err := interpreter.Run(source); err != nil {
+			t.Fatalf("Run(%q) returned %v", source, err)
+		}
+	}
+
+	if got := stdout.String(); got != "2\n" {
+		t.Errorf("output = %q, want %q", got, "2\n")
+	}
+}
@@ -0,0 +1,141 @@
+package lox
+
+import (
+	"fmt"
+)
+
+func (p *PrintStmt) Execute(interpreter *Interpreter, scope *Scope) error {
+	val, err := p.expression.Evaluate(interpreter, scope)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(interpreter.stdout, Stringify(val))
+	return nil
+}
+
+func (e *ExpressionStmt) Execute(interpreter *Interpreter, scope *Scope) error {
+	_, err := e.expression.Evaluate(interpreter, scope)
+	return err
+}
+
+func (v *VarStmt) Execute(interpreter *Interpreter, scope *Scope) error {
+	var val Value
+	if v.initializer != nil {
+		value, err := v.initializer.Evaluate(interpreter, scope)
+		if err != nil {
+			return err
+		}
+		val = value
+	}
+
+	scope.setScopeValue(v.name.lexeme, val)
+	return nil
+}
+
+func (b *BlockStmt) Execute(interpreter *Interpreter, scope *Scope) error {
+	// statements in a block run in a new scope nested inside the current one
+	return executeBlock(interpreter, b.statements, NewScope(scope))
+}
+
+func executeBlock(interpreter *Interpreter, stmts []Stmt, scope *Scope) error {
+	for _, stmt := range stmts {
+		if err := stmt.Execute(interpreter, scope); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *IfStmt) Execute(interpreter *Interpreter, scope *Scope) error {
+	condition, err := i.condition.Evaluate(interpreter, scope)
+	if err != nil {
+		return err
+	}
+
+	if isTruthy(condition) {
+		return i.thenBranch.Execute(interpreter, scope)
+	} else if i.elseBranch != nil {
+		return i.elseBranch.Execute(interpreter, scope)
+	}
+
+	return nil
+}
+
+func (w *WhileStmt) Execute(interpreter *Interpreter, scope *Scope) error {
+	for {
+		condition, err := w.condition.Evaluate(interpreter, scope)
+		if err != nil {
+			return err
+		}
+		if !isTruthy(condition) {
+			return nil
+		}
+
+		if err := w.body.Execute(interpreter, scope); err != nil {
+			return err
+		}
+	}
+}
+
+func (f *FunctionStmt) Execute(interpreter *Interpreter, scope *Scope) error {
+	scope.setScopeValue(f.name.lexeme, &Function{
+		declaration: f,
+		closure:     scope,
+	})
+	return nil
+}
+
+func (r *ReturnStmt) Execute(interpreter *Interpreter, scope *Scope) error {
+	var val Value
+	if r.value != nil {
+		value, err := r.value.Evaluate(interpreter, scope)
+		if err != nil {
+			return err
+		}
+		val = value
+	}
+
+	return &returnValue{value: val}
+}
+
+func (c *ClassStmt) Execute(interpreter *Interpreter, scope *Scope) error {
+	var superclass *Class
+	if c.superclass != nil {
+		value, err := c.superclass.Evaluate(interpreter, scope)
+		if err != nil {
+			return err
+		}
+		class, ok := value.(*Class)
+		if !ok {
+			return newRuntimeError(c.superclass.name, "superclass-not-class", "Superclass must be a class.")
+		}
+		superclass = class
+	}
+
+	scope.setScopeValue(c.name.lexeme, nil)
+
+	// methods of a subclass close over a scope that binds "super"
+	methodScope := scope
+	if superclass != nil {
+		methodScope = NewScope(scope)
+		methodScope.setScopeValue("super", superclass)
+	}
+
+	methods := make(map[string]*Function, len(c.methods))
+	for _, method := range c.methods {
+		methods[method.name.lexeme] = &Function{
+			declaration:   method,
+			closure:       methodScope,
+			isInitializer: method.name.lexeme == "init",
+		}
+	}
+
+	scope.setScopeValue(c.name.lexeme, &Class{
+		name:       c.name.lexeme,
+		superclass: superclass,
+		methods:    methods,
+	})
+	return nil
+}
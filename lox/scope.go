@@ -0,0 +1,71 @@
+package lox
+
+type Scope struct {
+	values    map[string]Value
+	enclosing *Scope
+}
+
+func NewScope(enclosing *Scope) *Scope {
+	return &Scope{
+		values:    make(map[string]Value),
+		enclosing: enclosing,
+	}
+}
+
+func (scope *Scope) setScopeValue(key string, val Value) {
+	scope.values[key] = val
+}
+
+func (scope *Scope) getScopeValue(key string) (Value, bool) {
+	// check for nil scope
+	if scope == nil {
+		return nil, false
+	}
+
+	// check current scope
+	if val, ok := scope.values[key]; ok {
+		return val, true
+	}
+
+	// check enclosing scope
+	if scope.enclosing != nil {
+		return scope.enclosing.getScopeValue(key)
+	}
+
+	return nil, false
+}
+
+// assignScopeValue updates key in the nearest scope that declares it. It
+// reports false, and assigns nothing, if no scope declares key.
+func (scope *Scope) assignScopeValue(key string, val Value) bool {
+	// check for nil scope
+	if scope == nil {
+		return false
+	}
+
+	// update current scope
+	if _, ok := scope.values[key]; ok {
+		scope.values[key] = val
+		return true
+	}
+
+	// check enclosing scope
+	return scope.enclosing.assignScopeValue(key, val)
+}
+
+// ancestor returns the scope distance levels out from this one.
+func (scope *Scope) ancestor(distance int) *Scope {
+	for i := 0; i < distance; i++ {
+		scope = scope.enclosing
+	}
+
+	return scope
+}
+
+func (scope *Scope) getScopeValueAt(distance int, key string) Value {
+	return scope.ancestor(distance).values[key]
+}
+
+func (scope *Scope) assignScopeValueAt(distance int, key string, val Value) {
+	scope.ancestor(distance).values[key] = val
+}
@@ -0,0 +1,68 @@
+package lox
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	colorBold  = "\x1b[1m"
+	colorRed   = "\x1b[1;31m"
+	colorBlue  = "\x1b[1;34m"
+	colorReset = "\x1b[0m"
+)
+
+// renderSnippet shows source line lineNo with the part of span on it
+// underlined, e.g.
+//
+//	3 | var x = @;
+//	  |         ^
+//
+// lineNo is the line the diagnostic reports, which for a span covering
+// several lines need not be the first. The underline then starts at the
+// beginning of the line or runs to its end as needed. It returns "" if the
+// line is not inside source.
+func renderSnippet(source string, span Span, lineNo int, color bool) string {
+	lines := strings.Split(source, "\n")
+	if lineNo < 1 || lineNo > len(lines) {
+		return ""
+	}
+	line := strings.TrimRight(lines[lineNo-1], "\r")
+	// columns count characters, not bytes
+	text := []rune(line)
+
+	start := 0
+	if span.start.line == lineNo {
+		start = span.start.column - 1
+	}
+	if start < 0 || start > len(text) {
+		return ""
+	}
+	end := len(text)
+	if span.end.line == lineNo {
+		end = min(span.end.column-1, len(text))
+	}
+	width := max(end-start, 1)
+
+	// keep tabs in the padding so the underline lines up with the text
+	padding := strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return r
+		}
+		return ' '
+	}, string(text[:start]))
+	underline := "^" + strings.Repeat("~", width-1)
+
+	gutter := fmt.Sprintf("%d", lineNo)
+	blank := strings.Repeat(" ", len(gutter))
+	if color {
+		gutter = colorBlue + gutter + " |" + colorReset
+		blank = colorBlue + blank + " |" + colorReset
+		underline = colorRed + underline + colorReset
+	} else {
+		gutter += " |"
+		blank += " |"
+	}
+
+	return fmt.Sprintf(" %s %s\n %s %s%s\n", gutter, line, blank, padding, underline)
+}
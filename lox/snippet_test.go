@@ -0,0 +1,110 @@
+package lox
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// span builds a span from 1-based line and column pairs; offsets aren't used
+// by renderSnippet.
+func span(startLine, startColumn, endLine, endColumn int) Span {
+	return Span{
+		start: Position{line: startLine, column: startColumn},
+		end:   Position{line: endLine, column: endColumn},
+	}
+}
+
+func TestRenderSnippet(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		span   Span
+		line   int
+		want   string
+	}{
+		{
+			name:   "single character",
+			source: "var x = @;",
+			span:   span(1, 9, 1, 10),
+			line:   1,
+			want:   " 1 | var x = @;\n   |         ^\n",
+		},
+		{
+			name:   "several characters",
+			source: "print 1;\nprint nope;",
+			span:   span(2, 7, 2, 11),
+			line:   2,
+			want:   " 2 | print nope;\n   |       ^~~~\n",
+		},
+		{
+			name:   "tabs are kept in the padding",
+			source: "\tvar x = @;",
+			span:   span(1, 10, 1, 11),
+			line:   1,
+			want:   " 1 | \tvar x = @;\n   | \t        ^\n",
+		},
+		{
+			name:   "first line of a multi-line span",
+			source: "print \"abc\ndef\nghi;",
+			span:   span(1, 7, 3, 5),
+			line:   1,
+			want:   " 1 | print \"abc\n   |       ^~~~\n",
+		},
+		{
+			name:   "last line of a multi-line span",
+			source: "print \"abc\ndef\nghi;",
+			span:   span(1, 7, 3, 5),
+			line:   3,
+			want:   " 3 | ghi;\n   | ^~~~\n",
+		},
+		{
+			name:   "columns count characters",
+			source: "var é = @;",
+			span:   span(1, 9, 1, 10),
+			line:   1,
+			want:   " 1 | var é = @;\n   |         ^\n",
+		},
+		{
+			name:   "wide gutter",
+			source: strings.Repeat("\n", 9) + "x;",
+			span:   span(10, 1, 10, 2),
+			line:   10,
+			want:   " 10 | x;\n    | ^\n",
+		},
+		{
+			name:   "line outside the source",
+			source: "print 1;",
+			span:   span(5, 1, 5, 2),
+			line:   5,
+			want:   "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := renderSnippet(test.source, test.span, test.line, false); got != test.want {
+				t.Errorf("renderSnippet = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRenderSnippetColor(t *testing.T) {
+	got := renderSnippet("var x = @;", span(1, 9, 1, 10), 1, true)
+	if !strings.Contains(got, colorRed+"^"+colorReset) || !strings.Contains(got, colorBlue) {
+		t.Errorf("renderSnippet = %q, want a colored underline and gutter", got)
+	}
+}
+
+func TestReportShowsHeaderLine(t *testing.T) {
+	var stderr bytes.Buffer
+	interpreter := New(Options{Stdout: &bytes.Buffer{}, Stderr: &stderr, Snippets: true})
+	interpreter.Run("print \"abc\ndef\nghi;")
+
+	// the unterminated string is reported where the input ran out
+	want := "[line 3] Error: Unterminated string.\n 3 | ghi;\n   | ^~~~\n"
+	if got := stderr.String(); !strings.HasPrefix(got, want) {
+		t.Errorf("stderr = %q, want it to start with %q", got, want)
+	}
+}